@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cg14823/gomaze/maze"
+	"github.com/cg14823/gomaze/pathfinding"
+)
+
+// benchResult adds up the results of a solver over every maze of a benchmark it solved.
+type benchResult struct {
+	solved  int
+	failed  int
+	elapsed time.Duration
+	steps   uint64
+	visited int
+	path    int
+	cost    int
+}
+
+func runBench(fs *flag.FlagSet, args []string) error {
+	var n int
+	var algos string
+	generator := addGeneratorFlags(fs, 100)
+	fs.IntVar(&n, "n", 20, "Number of mazes to solve")
+	fs.StringVar(&algos, "algos", strings.Join(pathfinding.List(), ","),
+		"Comma separated list of path finding algorithms to time")
+	err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if n < 1 {
+		return usageErrorf("-n must be at least 1")
+	}
+
+	rows, cols, err := generator.size()
+	if err != nil {
+		return err
+	}
+
+	opts, err := generator.options(rows, cols)
+	if err != nil {
+		return err
+	}
+
+	solvers, err := getSolvers(algos)
+	if err != nil {
+		return err
+	}
+
+	// every maze gets the next seed so a run can be repeated with -seed
+	seed := generator.seed
+	if !flagPassed(fs, "seed") {
+		seed = rand.New(rand.NewSource(time.Now().UnixNano())).Int63()
+	}
+
+	fmt.Fprintf(os.Stderr, "Solving %d %dx%d mazes from seed %d\n", n, rows, cols, seed)
+	var generation time.Duration
+	results := make([]benchResult, len(solvers))
+	for i := 0; i < n; i++ {
+		start := time.Now()
+		m := maze.NewMaze(rows, cols, append(opts, maze.WithSeed(seed+int64(i)))...)
+		generation += time.Since(start)
+
+		for j, s := range solvers {
+			result, err := s.solver.Solve(m, nil)
+			if err != nil {
+				// the solvers that walk the maze can get lost on mazes with loops, that is part of the benchmark
+				results[j].failed++
+				continue
+			}
+
+			results[j].solved++
+			results[j].elapsed += result.Elapsed
+			results[j].steps += result.Steps
+			results[j].visited += result.Visited
+			results[j].path += len(result.Path)
+			results[j].cost += result.Cost
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "algorithm\tmean time\tmean steps\tmean visited\tmean path\tmean cost\tfailed\t\n")
+	for j, s := range solvers {
+		r := results[j]
+		if r.solved == 0 {
+			fmt.Fprintf(w, "%s\t-\t-\t-\t-\t-\t%d\t\n", s.name, r.failed)
+			continue
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t\n", s.name, r.elapsed/time.Duration(r.solved),
+			r.steps/uint64(r.solved), r.visited/r.solved, r.path/r.solved, r.cost/r.solved, r.failed)
+	}
+
+	fmt.Fprintf(w, "generation (%s)\t%s\t\t\t\t\t\t\n", generator.algo, generation/time.Duration(n))
+	return w.Flush()
+}
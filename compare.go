@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image/color"
+	"os"
+	"strings"
+
+	"github.com/cg14823/gomaze/maze"
+	"github.com/cg14823/gomaze/pathfinding"
+)
+
+// compareColours are the colours the paths of each compared algorithm are drawn with in order.
+var compareColours = []color.Color{
+	color.RGBA{
+		B: 250,
+		A: 150,
+	},
+	color.RGBA{
+		G: 255,
+		A: 130,
+	},
+	color.RGBA{
+		R: 255,
+		A: 200,
+	},
+	color.RGBA{
+		R: 255,
+		G: 165,
+		A: 180,
+	},
+	color.RGBA{
+		G: 200,
+		B: 200,
+		A: 160,
+	},
+}
+
+func runCompare(fs *flag.FlagSet, args []string) error {
+	var algosToCompare string
+	input := addInputFlags(fs)
+	output := addOutputFlags(fs)
+	animation := addAnimationFlags(fs)
+	fs.StringVar(&algosToCompare, "algos", strings.Join(pathfinding.List(), ","),
+		"Comma separated list of path finding algorithms to compare")
+	err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	solvers, err := getSolvers(algosToCompare)
+	if err != nil {
+		return err
+	}
+
+	m, err := input.load()
+	if err != nil {
+		return err
+	}
+
+	format, fileOut, err := output.resolve(m)
+	if err != nil {
+		return err
+	}
+
+	paths := make([][]*maze.CellIndex, 0)
+	colours := make([]color.Color, 0)
+	recorders := make([]*pathfinding.Recorder, 0)
+	for i, s := range solvers {
+		tracer, recorder := animation.searchTracer(s.name)
+		result, err := s.solver.Solve(m, tracer)
+		if recorder != nil {
+			recorders = append(recorders, recorder)
+		}
+
+		if err != nil {
+			// the solvers that walk the maze can get lost where the searches would not, the rest still compare
+			fmt.Fprintf(os.Stderr, "%s failed to find path after %d steps: %v\n", s.name, result.Steps, err)
+			continue
+		}
+
+		printResult(m, s.name, result)
+		paths = append(paths, result.Path)
+		colours = append(colours, compareColours[i%len(compareColours)])
+	}
+
+	if animation.fileOut != "" {
+		err := saveSearchAnimation(m, animation.fileOut, animation.every, recorders)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(paths) == 0 {
+		return errors.New("none of the algorithms found a path")
+	}
+
+	return writeImage(m, fileOut, format, paths, colours)
+}
+
+type namedSolver struct {
+	name   string
+	solver pathfinding.Solver
+}
+
+// getSolvers looks up every solver in a comma separated list of names.
+func getSolvers(names string) ([]namedSolver, error) {
+	solvers := make([]namedSolver, 0)
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		solver, err := pathfinding.Get(name)
+		if err != nil {
+			return nil, &usageError{msg: err.Error()}
+		}
+
+		solvers = append(solvers, namedSolver{
+			name:   name,
+			solver: solver,
+		})
+	}
+
+	return solvers, nil
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cg14823/gomaze/maze"
+)
+
+// generatorFlags are the flags of the commands that generate mazes.
+type generatorFlags struct {
+	fs        *flag.FlagSet
+	cells     int
+	rows      int
+	cols      int
+	algo      string
+	seed      int64
+	placement string
+	start     string
+	end       string
+	braid     float64
+	passages  float64
+	maxCost   int
+	costImage string
+}
+
+func addGeneratorFlags(fs *flag.FlagSet, cells int) *generatorFlags {
+	g := &generatorFlags{fs: fs}
+	fs.IntVar(&g.cells, "cells", cells, "The numbers of cell across and wide for the maze")
+	fs.IntVar(&g.rows, "rows", 0, "The number of rows in the maze, overrides -cells")
+	fs.IntVar(&g.cols, "cols", 0, "The number of columns in the maze, overrides -cells")
+	fs.StringVar(&g.algo, "algo", "prim", fmt.Sprintf("The algorithm used to generate the maze available are %v",
+		maze.GeneratorNames()))
+	fs.Int64Var(&g.seed, "seed", 0, "Seed used to generate the maze, by default a random one is picked")
+	fs.StringVar(&g.placement, "placement", "border", fmt.Sprintf("How the start and end of the maze are "+
+		"picked available are %v", maze.PlacementNames()))
+	fs.StringVar(&g.start, "start", "", "Cell to start the maze in as row,col, needs -end")
+	fs.StringVar(&g.end, "end", "", "Cell to end the maze in as row,col, needs -start")
+	fs.Float64Var(&g.braid, "braid", 0,
+		"Fraction of dead ends to remove, between 0 and 1, so there is more than one way through the maze")
+	fs.Float64Var(&g.passages, "extra-passages", 0,
+		"Fraction of the walls left between cells to knock down at random, between 0 and 1")
+	fs.IntVar(&g.maxCost, "costs", 0, fmt.Sprintf("Cover the maze in patches costing up to this much to move "+
+		"through, up to %d", maze.MaxCost))
+	fs.StringVar(&g.costImage, "costs-image", "", "PNG image whose dark parts cost more to move through, "+
+		"up to -costs or 9")
+	return g
+}
+
+// size returns the rows and columns of the maze to generate.
+func (g *generatorFlags) size() (int, int, error) {
+	rows, cols := g.rows, g.cols
+	if rows == 0 {
+		rows = g.cells
+	}
+
+	if cols == 0 {
+		cols = g.cells
+	}
+
+	if rows < 1 || cols < 1 {
+		return 0, 0, usageErrorf("the maze needs at least one row and one column")
+	}
+
+	return rows, cols, nil
+}
+
+// options returns the options to generate a maze of rows by cols with, apart from the seed.
+func (g *generatorFlags) options(rows, cols int) ([]maze.Option, error) {
+	generator, err := maze.GetGenerator(g.algo)
+	if err != nil {
+		return nil, &usageError{msg: err.Error()}
+	}
+
+	if g.braid < 0 || g.braid > 1 || g.passages < 0 || g.passages > 1 {
+		return nil, usageErrorf("-braid and -extra-passages must be between 0 and 1")
+	}
+
+	if g.maxCost < 0 || g.maxCost > maze.MaxCost {
+		return nil, usageErrorf("-costs must be between 0 and %d", maze.MaxCost)
+	}
+
+	opts := []maze.Option{
+		maze.WithGenerator(generator),
+		maze.WithBraid(g.braid),
+		maze.WithExtraPassages(g.passages),
+		maze.WithRandomCosts(uint8(g.maxCost)),
+	}
+
+	if g.costImage != "" {
+		img, err := loadImage(g.costImage)
+		if err != nil {
+			return nil, err
+		}
+
+		maxCost := g.maxCost
+		if maxCost == 0 {
+			maxCost = 9
+		}
+
+		opts = append(opts, maze.WithCostImage(img, uint8(maxCost)))
+	}
+
+	if (g.start == "") != (g.end == "") {
+		return nil, usageErrorf("-start and -end must be given together")
+	}
+
+	if g.start == "" {
+		placement, err := maze.GetPlacement(g.placement)
+		if err != nil {
+			return nil, &usageError{msg: err.Error()}
+		}
+
+		return append(opts, maze.WithPlacement(placement)), nil
+	}
+
+	if flagPassed(g.fs, "placement") {
+		return nil, usageErrorf("-start and -end cannot be used with -placement")
+	}
+
+	var fixed maze.FixedPlacement
+	fixed.Start, err = parseCell("-start", g.start, rows, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	fixed.End, err = parseCell("-end", g.end, rows, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(opts, maze.WithPlacement(fixed)), nil
+}
+
+// generate creates the maze, see generateMaze.
+func (g *generatorFlags) generate(animateGeneration string, animateEvery int) (*maze.Maze, error) {
+	rows, cols, err := g.size()
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := g.options(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	if flagPassed(g.fs, "seed") {
+		opts = append(opts, maze.WithSeed(g.seed))
+	}
+
+	return generateMaze(rows, cols, opts, animateGeneration, animateEvery)
+}
+
+// parseCell parses the value of the flag name as a row,col cell inside a maze of rows by cols.
+func parseCell(name, value string, rows, cols int) (maze.CellIndex, error) {
+	var c maze.CellIndex
+	_, err := fmt.Sscanf(value, "%d,%d", &c.Row, &c.Col)
+	if err != nil {
+		return maze.CellIndex{}, usageErrorf("%s must be row,col but is %q", name, value)
+	}
+
+	if c.Row < 0 || c.Row >= rows || c.Col < 0 || c.Col >= cols {
+		return maze.CellIndex{}, usageErrorf("%s %s is outside the %dx%d maze", name, value, rows, cols)
+	}
+
+	return c, nil
+}
+
+// inputFlags are the flags of the commands that read a saved maze.
+type inputFlags struct {
+	in    string
+	pitch int
+}
+
+func addInputFlags(fs *flag.FlagSet) *inputFlags {
+	i := &inputFlags{}
+	fs.StringVar(&i.in, "in", "-",
+		"Maze to read, saved as JSON or binary, drawn as ASCII art or a PNG image, - reads stdin")
+	fs.IntVar(&i.pitch, "pitch", 0,
+		"Pixels from one cell to the next when -in is an image, by default it is detected")
+	return i
+}
+
+func (i *inputFlags) load() (*maze.Maze, error) {
+	return loadMaze(i.in, i.pitch)
+}
+
+// outputFlags are the flags of the commands that draw mazes.
+type outputFlags struct {
+	fileOut   string
+	format    string
+	textStyle string
+	termWidth int
+}
+
+func addOutputFlags(fs *flag.FlagSet) *outputFlags {
+	o := &outputFlags{}
+	fs.StringVar(&o.fileOut, "o", "",
+		"File to draw the maze in, - for stdout, by default out/maze-<rows>x<cols>-<time>.<format> for images "+
+			"and stdout for text")
+	fs.StringVar(&o.format, "format", "",
+		"Output format [png, svg, text, ansi], by default picked from the -o extension")
+	fs.StringVar(&o.textStyle, "text-style", "unicode", "How -format text draws the maze [ascii, unicode, blocks]")
+	fs.IntVar(&o.termWidth, "term-width", 0, "Columns -format ansi fits the maze in, by default $COLUMNS or 80")
+	return o
+}
+
+// resolve returns the format to draw m in and the file to draw it to, - for stdout.
+func (o *outputFlags) resolve(m *maze.Maze) (outputFormat, string, error) {
+	format := o.format
+	if format == "" {
+		format = "png"
+		switch strings.ToLower(filepath.Ext(o.fileOut)) {
+		case ".svg":
+			format = "svg"
+		case ".txt":
+			format = "text"
+		}
+	}
+
+	if format != "png" && format != "svg" && format != "text" && format != "ansi" {
+		return outputFormat{}, "", usageErrorf("unknown format %q", format)
+	}
+
+	out := outputFormat{
+		name:      format,
+		termWidth: o.termWidth,
+	}
+
+	if out.termWidth == 0 {
+		out.termWidth = terminalWidth()
+	}
+
+	var err error
+	out.textStyle, err = maze.ParseTextStyle(o.textStyle)
+	if err != nil {
+		return outputFormat{}, "", &usageError{msg: err.Error()}
+	}
+
+	fileOut := o.fileOut
+	if fileOut == "" {
+		fileOut = "-"
+		if format == "png" || format == "svg" {
+			fileOut = fmt.Sprintf("out/maze-%dx%d-%d.%s", m.Rows, m.Cols, time.Now().Unix(), format)
+		}
+	}
+
+	return out, fileOut, nil
+}
+
+// animationFlags are the flags of the commands that can animate the searches.
+type animationFlags struct {
+	fileOut string
+	every   int
+	trace   bool
+}
+
+func addAnimationFlags(fs *flag.FlagSet) *animationFlags {
+	a := &animationFlags{}
+	fs.StringVar(&a.fileOut, "animate", "", "GIF file to write an animation of the searches to")
+	fs.IntVar(&a.every, "animate-every", 0,
+		"Number of cells searched between animation frames, by default picked to give around 200 frames")
+	fs.BoolVar(&a.trace, "trace", false, "Log every cell the path finding algorithms expand to stderr")
+	return a
+}
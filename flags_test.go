@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/cg14823/gomaze/maze"
+)
+
+func TestParseCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  maze.CellIndex
+		ok    bool
+	}{
+		{"0,0", maze.CellIndex{}, true},
+		{"4,6", maze.CellIndex{Row: 4, Col: 6}, true},
+		{"2,3", maze.CellIndex{Row: 2, Col: 3}, true},
+		{"5,0", maze.CellIndex{}, false},
+		{"0,7", maze.CellIndex{}, false},
+		{"-1,0", maze.CellIndex{}, false},
+		{"0,-1", maze.CellIndex{}, false},
+		{"2", maze.CellIndex{}, false},
+		{"a,b", maze.CellIndex{}, false},
+		{"", maze.CellIndex{}, false},
+	}
+
+	for _, tt := range tests {
+		got, err := parseCell("-start", tt.value, 5, 7)
+		if tt.ok != (err == nil) || got != tt.want {
+			t.Fatalf("parseCell(%q) = %+v, %v, want %+v and ok %v", tt.value, got, err, tt.want, tt.ok)
+		}
+
+		if _, usage := err.(*usageError); err != nil && !usage {
+			t.Fatalf("parseCell(%q) returned %T, want a *usageError so the flags are printed", tt.value, err)
+		}
+	}
+}
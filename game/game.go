@@ -0,0 +1,117 @@
+// Package game lets a player walk through a maze in a terminal.
+package game
+
+import (
+	"time"
+
+	"github.com/cg14823/gomaze/maze"
+	"github.com/cg14823/gomaze/pathfinding"
+)
+
+// Direction is where the player tries to move.
+type Direction int
+
+const (
+	Up Direction = iota
+	Down
+	Left
+	Right
+)
+
+// Game is a player walking through a maze from its start to its end.
+type Game struct {
+	Maze *maze.Maze
+	// Route holds every cell the player has stood on in order, starting with the start of the maze.
+	Route []maze.CellIndex
+	Moves int
+	// Solution is the path from the end back to the start, only set once the player gives up.
+	Solution []*maze.CellIndex
+	started  time.Time
+	finished time.Time
+}
+
+// New puts a player on the start of m. The clock starts with the first move.
+func New(m *maze.Maze) *Game {
+	return &Game{
+		Maze:  m,
+		Route: []maze.CellIndex{m.Start},
+	}
+}
+
+// Position returns the cell the player is on.
+func (g *Game) Position() maze.CellIndex {
+	return g.Route[len(g.Route)-1]
+}
+
+// Move moves the player one cell in direction d and reports whether it could, walls block the player and
+// nothing moves once the game is over.
+func (g *Game) Move(d Direction) bool {
+	if g.Over() {
+		return false
+	}
+
+	pos := g.Position()
+	cell := g.Maze.Cells[pos.Row][pos.Col]
+	switch {
+	case d == Up && cell.Top:
+		pos.Row--
+	case d == Down && cell.Bottom:
+		pos.Row++
+	case d == Left && cell.Left:
+		pos.Col--
+	case d == Right && cell.Right:
+		pos.Col++
+	default:
+		return false
+	}
+
+	if g.Moves == 0 {
+		g.started = time.Now()
+	}
+
+	g.Moves++
+	g.Route = append(g.Route, pos)
+	if g.Won() {
+		g.finished = time.Now()
+	}
+
+	return true
+}
+
+// Won reports whether the player has reached the end.
+func (g *Game) Won() bool {
+	return g.Position() == g.Maze.End
+}
+
+// GiveUp ends the game and finds the solution with BFS.
+func (g *Game) GiveUp() error {
+	if g.Over() {
+		return nil
+	}
+
+	path, _, err := pathfinding.BFS(g.Maze)
+	if err != nil {
+		return err
+	}
+
+	g.Solution = path
+	g.finished = time.Now()
+	return nil
+}
+
+// Over reports whether the player has won or given up.
+func (g *Game) Over() bool {
+	return g.Won() || g.Solution != nil
+}
+
+// Elapsed returns the time since the first move, up to the end of the game.
+func (g *Game) Elapsed() time.Duration {
+	switch {
+	case g.Moves == 0:
+		return 0
+	case g.Over():
+		return g.finished.Sub(g.started)
+	default:
+		return time.Since(g.started)
+	}
+}
@@ -0,0 +1,288 @@
+package game
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"io"
+	"time"
+
+	"github.com/cg14823/gomaze/maze"
+)
+
+var (
+	routeColour = color.RGBA{
+		R: 100,
+		B: 100,
+		A: 255,
+	}
+
+	solutionColour = color.RGBA{
+		R: 255,
+		G: 165,
+		A: 255,
+	}
+
+	playerColour = color.RGBA{
+		R: 70,
+		G: 130,
+		B: 255,
+		A: 255,
+	}
+)
+
+// the longest a whole replay takes, short routes are replayed slower
+const (
+	replayLength   = 5 * time.Second
+	maxReplayDelay = 80 * time.Millisecond
+)
+
+// escapeTimeout is how long to wait for the rest of an escape sequence before taking ESC as a key of its own,
+// the terminal sends the whole sequence at once.
+const escapeTimeout = 50 * time.Millisecond
+
+type key int
+
+const (
+	keyOther key = iota
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyGiveUp
+	keyReplay
+	keyQuit
+)
+
+// Play runs the game reading keys from in and drawing to out, which should be a terminal in raw mode, see
+// MakeRaw. The maze is fitted to width columns. It returns once the player quits, or reaches the end or gives
+// up and has watched the replay of their route. When in has a SetReadDeadline method, like an *os.File
+// opened on the terminal, nothing more is read from it once Play returns, other readers are read from once
+// more and that key is thrown away.
+func Play(g *Game, in io.Reader, out io.Writer, width int) error {
+	keys := make(chan key)
+	done := make(chan struct{})
+	go readKeys(in, keys, done)
+	defer stopReading(in, keys, done)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	_, err := io.WriteString(out, "\x1b[?25l")
+	if err != nil {
+		return err
+	}
+	defer io.WriteString(out, "\x1b[?25h")
+
+	for !g.Over() {
+		err = draw(g, out, width, g.Route, "arrows or WASD to move, g to give up, q to quit")
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+			continue
+		case k, ok := <-keys:
+			if !ok || k == keyQuit {
+				return nil
+			}
+
+			err = handleKey(g, k)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for {
+		err = replay(g, out, width)
+		if err != nil {
+			return err
+		}
+
+		k, ok := <-keys
+		if !ok || k != keyReplay {
+			return nil
+		}
+	}
+}
+
+func handleKey(g *Game, k key) error {
+	switch k {
+	case keyUp:
+		g.Move(Up)
+	case keyDown:
+		g.Move(Down)
+	case keyLeft:
+		g.Move(Left)
+	case keyRight:
+		g.Move(Right)
+	case keyGiveUp:
+		return g.GiveUp()
+	}
+
+	return nil
+}
+
+// replay draws the route of the player a cell at a time.
+func replay(g *Game, out io.Writer, width int) error {
+	delay := replayLength / time.Duration(len(g.Route))
+	if delay > maxReplayDelay {
+		delay = maxReplayDelay
+	}
+
+	for i := 1; i <= len(g.Route); i++ {
+		err := draw(g, out, width, g.Route[:i], "replaying your route")
+		if err != nil {
+			return err
+		}
+
+		time.Sleep(delay)
+	}
+
+	result := fmt.Sprintf("you made it in %d moves and %s", g.Moves, g.Elapsed().Round(time.Second))
+	if !g.Won() {
+		result = fmt.Sprintf("you gave up after %d moves and %s, the shortest route is in orange", g.Moves,
+			g.Elapsed().Round(time.Second))
+	}
+
+	return draw(g, out, width, g.Route, result+", r to replay, any other key to quit")
+}
+
+// draw clears the screen and draws the maze with the route, the solution once the player gives up and the
+// player on top, followed by a status line.
+func draw(g *Game, out io.Writer, width int, route []maze.CellIndex, help string) error {
+	paths := make([][]*maze.CellIndex, 0, 3)
+	colours := make([]color.Color, 0, 3)
+	if g.Solution != nil {
+		paths = append(paths, g.Solution)
+		colours = append(colours, solutionColour)
+	}
+
+	walked := make([]*maze.CellIndex, len(route))
+	for i := range route {
+		walked[i] = &route[i]
+	}
+
+	paths = append(paths, walked, walked[len(walked)-1:])
+	colours = append(colours, routeColour, playerColour)
+
+	var buf bytes.Buffer
+	buf.WriteString("\x1b[H\x1b[2J")
+	err := g.Maze.WriteANSI(&buf, maze.ANSIOptions{
+		Width:   width,
+		Paths:   paths,
+		Colours: colours,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(&buf, "moves %d, time %s\n%s\n", g.Moves, g.Elapsed().Round(time.Second), help)
+	_, err = out.Write(buf.Bytes())
+	return err
+}
+
+// stopReading stops readKeys, waiting for it when the read it is blocked in can be cut short with a deadline.
+func stopReading(in io.Reader, keys <-chan key, done chan<- struct{}) {
+	close(done)
+	d, ok := in.(interface{ SetReadDeadline(t time.Time) error })
+	if !ok || d.SetReadDeadline(time.Now()) != nil {
+		return
+	}
+
+	for range keys {
+	}
+}
+
+// readKeys sends every key read from in to keys until in fails or done is closed, then closes keys.
+func readKeys(in io.Reader, keys chan<- key, done <-chan struct{}) {
+	defer close(keys)
+	received := make(chan byte)
+	go readBytes(in, received, done)
+	for b := range received {
+		k := keyOther
+		switch b {
+		case 'w', 'W', 'k':
+			k = keyUp
+		case 's', 'S', 'j':
+			k = keyDown
+		case 'a', 'A', 'h':
+			k = keyLeft
+		case 'd', 'D', 'l':
+			k = keyRight
+		case 'g', 'G':
+			k = keyGiveUp
+		case 'r', 'R':
+			k = keyReplay
+		case 'q', 'Q', 3, 4:
+			// 3 and 4 are ctrl-c and ctrl-d, which raw mode no longer turns into signals
+			k = keyQuit
+		case 0x1b:
+			k = readArrow(received)
+		}
+
+		select {
+		case keys <- k:
+		case <-done:
+			return
+		}
+	}
+}
+
+// readBytes sends every byte read from in to received until in fails or done is closed, then closes received. The
+// bytes are sent from a goroutine of their own so an escape sequence can be waited for with a timeout.
+func readBytes(in io.Reader, received chan<- byte, done <-chan struct{}) {
+	defer close(received)
+	buf := make([]byte, 64)
+	for {
+		n, err := in.Read(buf)
+		for _, b := range buf[:n] {
+			select {
+			case received <- b:
+			case <-done:
+				return
+			}
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readArrow reads the rest of an escape sequence, arrow keys are sent as ESC [ A to D. ESC on its own gives
+// keyOther once no more of a sequence arrives for escapeTimeout.
+func readArrow(received <-chan byte) key {
+	next := func() (byte, bool) {
+		select {
+		case b, ok := <-received:
+			return b, ok
+		case <-time.After(escapeTimeout):
+			return 0, false
+		}
+	}
+
+	if b, ok := next(); !ok || b != '[' {
+		return keyOther
+	}
+
+	b, ok := next()
+	if !ok {
+		return keyOther
+	}
+
+	switch b {
+	case 'A':
+		return keyUp
+	case 'B':
+		return keyDown
+	case 'C':
+		return keyRight
+	case 'D':
+		return keyLeft
+	}
+
+	return keyOther
+}
@@ -0,0 +1,80 @@
+package game
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cg14823/gomaze/maze"
+)
+
+func TestReadKeys(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	keys := make(chan key)
+	done := make(chan struct{})
+	go readKeys(r, keys, done)
+
+	// a lone ESC at the end is only a key once the rest of a sequence does not arrive
+	_, err = w.WriteString("w\x1b[Bhq\x1bx\x1b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []key{keyUp, keyDown, keyLeft, keyQuit, keyOther, keyOther} {
+		select {
+		case k := <-keys:
+			if k != want {
+				t.Fatalf("got key %d, want %d", k, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no key read, want %d", want)
+		}
+	}
+
+	stopReading(r, keys, done)
+	if _, ok := <-keys; ok {
+		t.Fatal("keys are still being read")
+	}
+}
+
+func TestPlayStopsReading(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	_, err = w.WriteString("dq")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Play(New(maze.NewMaze(5, 5, maze.WithSeed(1))), r, ioutil.Discard, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a key pressed after the game is left for whoever reads the terminal next
+	_, err = w.WriteString("x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.SetReadDeadline(time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := make([]byte, 1)
+	if _, err = r.Read(b); err != nil || b[0] != 'x' {
+		t.Fatalf("read %q and %v after the game, want x", b, err)
+	}
+}
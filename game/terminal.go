@@ -0,0 +1,6 @@
+package game
+
+import "errors"
+
+// ErrNotTerminal is returned by MakeRaw when the file is not a terminal it can put in raw mode.
+var ErrNotTerminal = errors.New("not a terminal")
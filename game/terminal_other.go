@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package game
+
+// MakeRaw is only supported on Linux and macOS, elsewhere it always returns ErrNotTerminal.
+func MakeRaw(fd int) (func() error, error) {
+	return nil, ErrNotTerminal
+}
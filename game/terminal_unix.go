@@ -0,0 +1,46 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package game
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// MakeRaw puts the terminal fd in raw mode so every key is read as it is pressed without being echoed, and
+// returns a function restoring the mode it was in. Output processing is left on so new lines still return
+// the cursor to the start of the line.
+func MakeRaw(fd int) (func() error, error) {
+	var old syscall.Termios
+	err := ioctlTermios(fd, ioctlGetTermios, &old)
+	if err != nil {
+		return nil, ErrNotTerminal
+	}
+
+	raw := old
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR |
+		syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	err = ioctlTermios(fd, ioctlSetTermios, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		return ioctlTermios(fd, ioctlSetTermios, &old)
+	}, nil
+}
+
+func ioctlTermios(fd int, request uintptr, t *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), request, uintptr(unsafe.Pointer(t)))
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
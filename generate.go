@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func runGenerate(fs *flag.FlagSet, args []string) error {
+	var fileOut, animateGeneration string
+	var animateEvery int
+	generator := addGeneratorFlags(fs, 25)
+	fs.StringVar(&fileOut, "o", "-", "File to save the maze to, as compact binary when it ends in .bin "+
+		"otherwise JSON, - for stdout")
+	fs.StringVar(&animateGeneration, "animate", "", "GIF file to write an animation of the generation to")
+	fs.IntVar(&animateEvery, "animate-every", 0,
+		"Number of walls removed between animation frames, by default picked to give around 200 frames")
+	err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	m, err := generator.generate(animateGeneration, animateEvery)
+	if err != nil {
+		return err
+	}
+
+	err = saveMaze(fileOut, m)
+	if err != nil {
+		return err
+	}
+
+	if fileOut != "-" {
+		fmt.Fprintln(os.Stderr, "Maze saved to", fileOut)
+	}
+
+	return nil
+}
@@ -1,148 +1,179 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
-	"image/color"
 	"os"
-	"strings"
-	"time"
-
-	"github.com/cg14823/gomaze/pathfinding"
+	"text/tabwriter"
+)
 
-	"github.com/cg14823/gomaze/maze"
+// the exit codes of gomaze, every command exits with exitUsage when it is called with the wrong flags
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
 )
 
-func main() {
-	var cells int
-	var pathFind, fileOut, algosToCompare string
-	flag.IntVar(&cells, "cells", 25, "The numbers of cell across and wide for the maze")
-	flag.StringVar(&pathFind, "path-find", "", "The path finding algorithm to use available are [bfs, stack]")
-	flag.StringVar(&algosToCompare, "compare-algos", "", "Comma separated list of algos to compare")
-	flag.StringVar(&fileOut, "file-out", "", "Image file with the maze")
-	flag.Parse()
-
-	if pathFind != "" && algosToCompare != "" {
-		fmt.Println("cannot provide both -path-find and -compare-algos")
-		os.Exit(1)
-	}
+// command is one of the subcommands of gomaze. run is given a flag set for the command to add its flags to.
+type command struct {
+	name    string
+	summary string
+	run     func(fs *flag.FlagSet, args []string) error
+}
 
-	m := maze.NewMaze(cells, cells)
-	fmt.Println("Done creating maze; producing image")
+var commands = []command{
+	{
+		name:    "generate",
+		summary: "Generate a maze and save it",
+		run:     runGenerate,
+	},
+	{
+		name:    "solve",
+		summary: "Solve a saved maze and draw the path found",
+		run:     runSolve,
+	},
+	{
+		name:    "render",
+		summary: "Draw a saved maze as an image or text",
+		run:     runRender,
+	},
+	{
+		name:    "compare",
+		summary: "Solve a saved maze with several algorithms and draw all their paths",
+		run:     runCompare,
+	},
+	{
+		name:    "stats",
+		summary: "Print statistics about a saved maze",
+		run:     runStats,
+	},
+	{
+		name:    "bench",
+		summary: "Time the path finding algorithms on many generated mazes",
+		run:     runBench,
+	},
+	{
+		name:    "play",
+		summary: "Walk through a maze in the terminal",
+		run:     runPlay,
+	},
+	{
+		name:    "serve",
+		summary: "Serve an HTTP API to generate, draw and solve mazes",
+		run:     runServe,
+	},
+}
 
-	if fileOut == "" {
-		fileOut = fmt.Sprintf("./out/maze-%dx%d-%d", cells, cells, time.Now().Unix())
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run runs the command named by the first argument and returns the exit code.
+func run(args []string) int {
+	if len(args) == 0 {
+		printUsage()
+		return exitUsage
 	}
 
-	if pathFind != "" {
-		err := singlePathFind(pathFind, fileOut, m)
-		if err != nil {
-			fmt.Println("Failed:", err.Error())
-			os.Exit(1)
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		if len(args) > 1 {
+			return run([]string{args[1], "-h"})
 		}
 
-		return
+		printUsage()
+		return exitOK
 	}
 
-	if algosToCompare != "" {
-		err := compareAlgos(algosToCompare, fileOut, m)
-		if err != nil {
-			fmt.Println("Failed:", err.Error())
-			os.Exit(1)
+	for _, c := range commands {
+		if c.name != args[0] {
+			continue
 		}
 
-		return
-	}
+		err := c.run(newFlagSet(c), args[1:])
+		var usage *usageError
+		switch {
+		case err == nil, errors.Is(err, flag.ErrHelp):
+			return exitOK
+		case errors.As(err, &usage):
+			// flag errors are printed by the flag set along with the usage
+			if usage.msg != "" {
+				fmt.Fprintf(os.Stderr, "gomaze %s: %s\nRun 'gomaze %s -h' for usage.\n", c.name, usage.msg, c.name)
+			}
 
-	err := m.Image(fileOut)
-	if err != nil {
-		fmt.Println(err.Error())
-		os.Exit(1)
+			return exitUsage
+		default:
+			fmt.Fprintf(os.Stderr, "gomaze %s: %s\n", c.name, err.Error())
+			return exitFailure
+		}
 	}
 
-	fmt.Println("Image done")
+	fmt.Fprintf(os.Stderr, "gomaze: unknown command %q\n\n", args[0])
+	printUsage()
+	return exitUsage
 }
 
-func singlePathFind(algo, fileOut string, m *maze.Maze) error {
-	var path []*maze.CellIndex
-	var steps uint64
-	var err error
-
-	switch algo{
-	case "bfs":
-		path, steps, err = pathfinding.BFS(m)
-	case "dfs":
-		path, steps, err = pathfinding.DFS(m)
-	case "astar":
-		path, steps, err = pathfinding.Astart(m)
-	default:
-		return fmt.Errorf("unrecognized alogrithm: %s", algo)
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "usage: gomaze <command> [flags]\n\nCommands:\n")
+	w := tabwriter.NewWriter(os.Stderr, 0, 4, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %s\t%s\n", c.name, c.summary)
 	}
 
-	if err != nil {
-		return fmt.Errorf("%s failed to find path after %d steps", algo, steps)
-	}
+	w.Flush()
+	fmt.Fprintf(os.Stderr, "\nRun 'gomaze <command> -h' for the flags of a command.\n")
+}
 
-	err = m.ImageWithPath(path, fileOut, color.RGBA{
-		R: 100,
-		G: 0,
-		B: 100,
-		A: 255,
-	})
-	if err != nil {
-		return fmt.Errorf("could not create image: %w", err.Error())
+func newFlagSet(c command) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: gomaze %s [flags]\n\n%s.\n\nFlags:\n", c.name, c.summary)
+		fs.PrintDefaults()
 	}
 
-	return nil
+	return fs
 }
 
-func compareAlgos(algosToCompare, fileOut string, m *maze.Maze) error {
-	algos := strings.Split(algosToCompare, ",")
-	paths := make([][]*maze.CellIndex, 0)
-	colours := make([]color.Color, 0)
-	for _, a := range algos {
-		var out []*maze.CellIndex
-		var steps uint64
-		var err error
-		var c color.Color
-
-		switch strings.TrimSpace(a) {
-		case "dfs":
-			out, steps, err = pathfinding.BFS(m)
-			c = color.RGBA{
-				B: 250,
-				A: 150,
-			}
-		case "bfs":
-			out, steps, err = pathfinding.DFS(m)
-			c = color.RGBA{
-				G: 255,
-				A: 130,
-			}
-		case "astar":
-			out, steps, err = pathfinding.Astart(m)
-			c = color.RGBA{
-				R: 255,
-				A: 200,
-			}
-		default:
-			return fmt.Errorf("unknown algo `%s`", a)
-		}
+// usageError is returned by commands called the wrong way, gomaze then exits with exitUsage.
+type usageError struct {
+	msg string
+}
 
-		if err != nil {
-			return err
-		}
+func (e *usageError) Error() string {
+	return e.msg
+}
 
-		fmt.Printf("Path found using %s took %d steps path length %d\n", a, steps, len(out))
-		paths = append(paths, out)
-		colours = append(colours, c)
-		m.UnVisitAll()
+func usageErrorf(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// parseFlags parses the flags of a command, which takes no other arguments.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err == flag.ErrHelp {
+		return err
 	}
 
-	err := m.ImageWithMultiplePaths(paths, fileOut, colours)
 	if err != nil {
-		return fmt.Errorf("could not create image: %w", err)
+		return &usageError{}
+	}
+
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument %q", fs.Arg(0))
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// flagPassed reports whether the flag was set on the command line rather than left to its default.
+func flagPassed(fs *flag.FlagSet, name string) bool {
+	var passed bool
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			passed = true
+		}
+	})
+
+	return passed
+}
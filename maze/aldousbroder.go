@@ -0,0 +1,32 @@
+package maze
+
+import "math/rand"
+
+// AldousBroder generates mazes using the Aldous-Broder algorithm. A random
+// walk wanders over the grid and links every cell the first time it is
+// entered. Like Wilson it produces uniform spanning trees but it is a lot
+// slower to finish on big mazes.
+type AldousBroder struct{}
+
+func (AldousBroder) Generate(m *Maze, r *rand.Rand, step StepFunc) {
+	current := CellIndex{
+		Row: r.Intn(m.Rows),
+		Col: r.Intn(m.Cols),
+	}
+
+	m.Cells[current.Row][current.Col].In = true
+	remaining := m.Rows*m.Cols - 1
+	for remaining > 0 {
+		neighbours := m.neighbours(current.Row, current.Col)
+		next := neighbours[r.Intn(len(neighbours))]
+		if m.Cells[next.Row][next.Col].Blocked() {
+			m.link(current, next)
+			remaining--
+			step.notify(m, current, next, func() []CellIndex {
+				return []CellIndex{next}
+			})
+		}
+
+		current = next
+	}
+}
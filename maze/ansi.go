@@ -0,0 +1,185 @@
+package maze
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+)
+
+// maxANSICellSize stops small mazes from filling the whole terminal when drawn to fit its width.
+const maxANSICellSize = 4
+
+// ANSIOptions configures WriteANSI.
+type ANSIOptions struct {
+	// Width is how many columns the terminal has, the maze is drawn as large as fits in it and shrunk when
+	// it does not fit with cells a single column wide. 0 draws cells a single column wide.
+	Width int
+	// Paths are painted over the maze in order, each in the colour at the same position of Colours.
+	Paths   [][]*CellIndex
+	Colours []color.Color
+	// Visited cells are painted in a lighter colour, like cells marked with VisitCell.
+	Visited []CellIndex
+}
+
+// WriteANSI draws the maze for a terminal with 24-bit colour using half block characters, so every line of
+// text holds two rows of square pixels. It uses the same colours as Image, the start in red and the end in
+// green.
+func (m *Maze) WriteANSI(w io.Writer, opts ANSIOptions) error {
+	cellSize := 1
+	if opts.Width > 0 {
+		cellSize = (opts.Width-1)/m.Cols - 1
+		if cellSize > maxANSICellSize {
+			cellSize = maxANSICellSize
+		}
+	}
+
+	shrink := 1
+	if cellSize < 1 {
+		cellSize = 1
+		shrink = (2*m.Cols + opts.Width) / opts.Width
+	}
+
+	img := m.terminalImage(cellSize, opts)
+	if shrink > 1 {
+		img = shrinkImage(img, shrink)
+	}
+
+	bw := bufio.NewWriter(w)
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y += 2 {
+		var last string
+		for x := b.Min.X; x < b.Max.X; x++ {
+			// the top pixel is the foreground of ▀ and the bottom one its background, an odd last row
+			// leaves the background of the terminal under it
+			code := ansiColour(38, img.At(x, y))
+			if y+1 < b.Max.Y {
+				code += ansiColour(48, img.At(x, y+1))
+			} else {
+				code += "\x1b[49m"
+			}
+
+			if code != last {
+				_, err := bw.WriteString(code)
+				if err != nil {
+					return err
+				}
+
+				last = code
+			}
+
+			_, err := bw.WriteString("▀")
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := bw.WriteString("\x1b[0m\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// terminalImage draws the maze with walls a pixel wide and no margin.
+func (m *Maze) terminalImage(cellSize int, opts ANSIOptions) *image.RGBA {
+	pitch := cellSize + 1
+	img := generateEmptyImage(m.Cols*pitch+1, m.Rows*pitch+1)
+	m.drawMap(img, cellSize, cellSize, 1, 1, 1, 1)
+
+	paint := func(c CellIndex, colour color.Color) {
+		// keep the start and end visible
+		if m.Cells[c.Row][c.Col].Start || m.Cells[c.Row][c.Col].End {
+			return
+		}
+
+		paintCell(img, 1+c.Col*pitch, 1+c.Row*pitch, cellSize, cellSize, colour)
+	}
+
+	for r := range m.Cells {
+		for c := range m.Cells[r] {
+			if m.Cells[r][c].Visited {
+				paint(CellIndex{Row: r, Col: c}, visitedColour)
+			}
+		}
+	}
+
+	for _, c := range opts.Visited {
+		paint(c, visitedColour)
+	}
+
+	for i, path := range opts.Paths {
+		colour := color.Color(searchPathColour)
+		if i < len(opts.Colours) {
+			colour = opts.Colours[i]
+		}
+
+		for j, c := range path {
+			paint(*c, colour)
+
+			// with cells this small the path is hard to follow unless the passages along it are painted too
+			if j > 0 {
+				prev := path[j-1]
+				x, y := 1+min(prev.Col, c.Col)*pitch, 1+min(prev.Row, c.Row)*pitch
+				if prev.Row == c.Row {
+					paintCell(img, x+cellSize, y, 1, cellSize, colour)
+				} else {
+					paintCell(img, x, y+cellSize, cellSize, 1, colour)
+				}
+			}
+		}
+	}
+
+	return img
+}
+
+// shrinkImage scales img down by factor, every new pixel takes the most common colour of the pixels it
+// covers, preferring anything that is not a wall or a passage so the start, end and paths do not vanish.
+func shrinkImage(img *image.RGBA, factor int) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, (b.Dx()+factor-1)/factor, (b.Dy()+factor-1)/factor))
+	for y := 0; y < out.Bounds().Dy(); y++ {
+		for x := 0; x < out.Bounds().Dx(); x++ {
+			counts := make(map[color.RGBA]int)
+			for yi := y * factor; yi < (y+1)*factor && yi < b.Dy(); yi++ {
+				for xi := x * factor; xi < (x+1)*factor && xi < b.Dx(); xi++ {
+					counts[img.RGBAAt(b.Min.X+xi, b.Min.Y+yi)]++
+				}
+			}
+
+			var best color.RGBA
+			bestCount, bestPlain := 0, true
+			for c, n := range counts {
+				plain := c == color.RGBA{A: 255} || c == color.RGBA{R: 255, G: 255, B: 255, A: 255}
+				if (bestPlain && !plain) || (plain == bestPlain && (n > bestCount || n == bestCount &&
+					packRGBA(c) < packRGBA(best))) {
+					best, bestCount, bestPlain = c, n, plain
+				}
+			}
+
+			out.SetRGBA(x, y, best)
+		}
+	}
+
+	return out
+}
+
+func packRGBA(c color.RGBA) uint32 {
+	return uint32(c.R)<<24 | uint32(c.G)<<16 | uint32(c.B)<<8 | uint32(c.A)
+}
+
+func ansiColour(code int, c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("\x1b[%d;2;%d;%d;%dm", code, r>>8, g>>8, b>>8)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
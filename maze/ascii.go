@@ -0,0 +1,375 @@
+package maze
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseError is returned by ParseASCII, Line and Col are where in the text the problem is, both
+// starting at 1. They are 0 when the problem is not at a single place, like a missing start.
+type ParseError struct {
+	Line int
+	Col  int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return "invalid ascii maze: " + e.Msg
+	}
+
+	return fmt.Sprintf("invalid ascii maze at line %d column %d: %s", e.Line, e.Col, e.Msg)
+}
+
+// ParseASCII reads a maze drawn as text. Two formats are understood, the one written by AsciiDraw
+// and the common one drawing corners with +, walls with - and | and marking the start and end
+// cells with S and E, a cell holding both is the start and the end:
+//
+//	+--+--+--+
+//	|S    |  |
+//	+--+  +  +
+//	|       E|
+//	+--+--+--+
+//
+// Cells can be any number of characters wide as long as they all are the same. Walls that neighbouring
+// cells disagree on or that leave the maze are reported as a *ParseError.
+func ParseASCII(r io.Reader) (*Maze, error) {
+	lines, first, err := readASCIILines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lines) == 0 {
+		return nil, &ParseError{Msg: "no maze found"}
+	}
+
+	switch lines[0][0] {
+	case '+':
+		return parseBoxASCII(lines, first)
+	case '_':
+		return parseAsciiDraw(lines, first)
+	default:
+		return nil, &ParseError{Line: first, Col: 1, Msg: fmt.Sprintf("unexpected %q, a maze starts with + or _",
+			lines[0][0])}
+	}
+}
+
+// readASCIILines returns the lines of the maze without blank lines around it and the line number of the
+// first one.
+func readASCIILines(r io.Reader) ([]string, int, error) {
+	lines := make([]string, 0)
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" || err == nil {
+			lines = append(lines, strings.TrimRight(line, "\r\n"))
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, 0, fmt.Errorf("could not read maze: %w", err)
+		}
+	}
+
+	first := 0
+	for first < len(lines) && strings.TrimSpace(lines[first]) == "" {
+		first++
+	}
+
+	last := len(lines)
+	for last > first && strings.TrimSpace(lines[last-1]) == "" {
+		last--
+	}
+
+	// leading spaces would shift every column so only trailing ones are dropped
+	lines = lines[first:last]
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t")
+	}
+
+	return lines, first + 1, nil
+}
+
+// parseBoxASCII parses mazes drawn with +, - and |.
+func parseBoxASCII(lines []string, first int) (*Maze, error) {
+	width := strings.IndexByte(lines[0][1:], '+')
+	if width < 1 {
+		return nil, &ParseError{Line: first, Col: 1, Msg: "top wall must have a + after every cell"}
+	}
+
+	pitch := width + 1
+	if (len(lines[0])-1)%pitch != 0 {
+		return nil, &ParseError{Line: first, Col: len(lines[0]),
+			Msg: fmt.Sprintf("top wall does not end on a cell %d characters wide", width)}
+	}
+
+	if len(lines)%2 != 1 || len(lines) < 3 {
+		return nil, &ParseError{Line: first + len(lines) - 1, Col: 1,
+			Msg: "maze must end with a line of walls under its last row"}
+	}
+
+	rows, cols := len(lines)/2, (len(lines[0])-1)/pitch
+	passages := make([][]uint8, rows)
+	for r := range passages {
+		passages[r] = make([]uint8, cols)
+	}
+
+	start, end := CellIndex{Row: -1}, CellIndex{Row: -1}
+	for i, line := range lines {
+		lineNumber := first + i
+		if len(line) > cols*pitch+1 {
+			return nil, &ParseError{Line: lineNumber, Col: cols*pitch + 2, Msg: "line is longer than the top wall"}
+		}
+
+		// trailing spaces are trimmed by editors so a short line is read as if it had them
+		line += strings.Repeat(" ", cols*pitch+1-len(line))
+		if i%2 == 0 {
+			// a line of walls above row i/2
+			r := i / 2
+			for c := 0; c <= cols; c++ {
+				if line[c*pitch] != '+' {
+					return nil, &ParseError{Line: lineNumber, Col: c*pitch + 1,
+						Msg: fmt.Sprintf("unexpected %q, corners are +", line[c*pitch])}
+				}
+
+				if c == cols {
+					break
+				}
+
+				segment := line[c*pitch+1 : (c+1)*pitch]
+				switch segment {
+				case strings.Repeat("-", width):
+					continue
+				case strings.Repeat(" ", width):
+				default:
+					return nil, &ParseError{Line: lineNumber, Col: c*pitch + 2,
+						Msg: fmt.Sprintf("wall %q must be all - or all spaces", segment)}
+				}
+
+				if r == 0 || r == rows {
+					return nil, &ParseError{Line: lineNumber, Col: c*pitch + 2, Msg: "maze opens outside"}
+				}
+
+				passages[r-1][c] |= passageBottom
+				passages[r][c] |= passageTop
+			}
+
+			continue
+		}
+
+		r := i / 2
+		for c := 0; c <= cols; c++ {
+			col := c * pitch
+			switch line[col] {
+			case '|':
+			case ' ':
+				if c == 0 || c == cols {
+					return nil, &ParseError{Line: lineNumber, Col: col + 1, Msg: "maze opens outside"}
+				}
+
+				passages[r][c-1] |= passageRight
+				passages[r][c] |= passageLeft
+			default:
+				return nil, &ParseError{Line: lineNumber, Col: col + 1,
+					Msg: fmt.Sprintf("unexpected %q, walls between cells are | or a space", line[col])}
+			}
+
+			if c == cols {
+				break
+			}
+
+			for x := col + 1; x < col+pitch; x++ {
+				var marker *CellIndex
+				switch line[x] {
+				case ' ':
+					continue
+				case 'S':
+					marker = &start
+				case 'E':
+					marker = &end
+				default:
+					return nil, &ParseError{Line: lineNumber, Col: x + 1,
+						Msg: fmt.Sprintf("unexpected %q, cells can only hold S or E", line[x])}
+				}
+
+				// S and E can share a cell when the maze starts where it ends, but each only appears once
+				if marker.Row >= 0 {
+					return nil, &ParseError{Line: lineNumber, Col: x + 1, Msg: fmt.Sprintf("second %c", line[x])}
+				}
+
+				*marker = CellIndex{Row: r, Col: c}
+			}
+		}
+	}
+
+	return fromParsedPassages(rows, cols, passages, start, end)
+}
+
+// parseAsciiDraw parses mazes written by AsciiDraw, where every cell takes three characters: its left
+// side, its bottom and its right side. An open side is drawn as _ so the marker of the start and end
+// cells hides whether their bottom is open, it is worked out from the rest of the maze. When either of them
+// could be the one joining two parts of the maze the first one in the text is opened.
+func parseAsciiDraw(lines []string, first int) (*Maze, error) {
+	if strings.Trim(lines[0], "_") != "" || len(lines[0])%3 != 0 {
+		return nil, &ParseError{Line: first, Col: 1, Msg: "top wall must be three _ per cell"}
+	}
+
+	if len(lines) < 2 {
+		return nil, &ParseError{Line: first + 1, Col: 1, Msg: "maze has no rows"}
+	}
+
+	rows, cols := len(lines)-1, len(lines[0])/3
+	passages := make([][]uint8, rows)
+	for r := range passages {
+		passages[r] = make([]uint8, cols)
+	}
+
+	start, end := CellIndex{Row: -1}, CellIndex{Row: -1}
+	hidden := make([]CellIndex, 0, 2)
+	for r := 0; r < rows; r++ {
+		line := lines[r+1]
+		lineNumber := first + r + 1
+		if len(line) != cols*3 {
+			return nil, &ParseError{Line: lineNumber, Col: 1,
+				Msg: fmt.Sprintf("line has %d characters but should have %d", len(line), cols*3)}
+		}
+
+		for c := 0; c < cols; c++ {
+			left, err := asciiDrawSide(line, c*3, lineNumber)
+			if err != nil {
+				return nil, err
+			}
+
+			right, err := asciiDrawSide(line, c*3+2, lineNumber)
+			if err != nil {
+				return nil, err
+			}
+
+			if c == 0 && left {
+				return nil, &ParseError{Line: lineNumber, Col: 1, Msg: "maze opens outside"}
+			}
+
+			if c == cols-1 && right {
+				return nil, &ParseError{Line: lineNumber, Col: c*3 + 3, Msg: "maze opens outside"}
+			}
+
+			if c > 0 && left != (passages[r][c-1]&passageRight != 0) {
+				return nil, &ParseError{Line: lineNumber, Col: c*3 + 1,
+					Msg: "left side does not match the right side of the cell before it"}
+			}
+
+			if left {
+				passages[r][c] |= passageLeft
+			}
+
+			if right {
+				passages[r][c] |= passageRight
+			}
+
+			if r > 0 && passages[r-1][c]&passageBottom != 0 {
+				passages[r][c] |= passageTop
+			}
+
+			switch line[c*3+1] {
+			case '_':
+				continue
+			case ' ':
+				if r == rows-1 {
+					return nil, &ParseError{Line: lineNumber, Col: c*3 + 2, Msg: "maze opens outside"}
+				}
+
+				passages[r][c] |= passageBottom
+			case 'S', 'E':
+				marker := &start
+				if line[c*3+1] == 'E' {
+					marker = &end
+				}
+
+				if marker.Row >= 0 {
+					return nil, &ParseError{Line: lineNumber, Col: c*3 + 2,
+						Msg: fmt.Sprintf("second %c", line[c*3+1])}
+				}
+
+				*marker = CellIndex{Row: r, Col: c}
+				if r < rows-1 {
+					hidden = append(hidden, *marker)
+				}
+			default:
+				return nil, &ParseError{Line: lineNumber, Col: c*3 + 2,
+					Msg: fmt.Sprintf("unexpected %q, the bottom of a cell is _, a space, S or E", line[c*3+1])}
+			}
+		}
+	}
+
+	// a single cell maze only shows its start
+	if end.Row < 0 && rows*cols == 1 {
+		end = start
+	}
+
+	openHiddenBottoms(rows, cols, passages, hidden)
+	return fromParsedPassages(rows, cols, passages, start, end)
+}
+
+// asciiDrawSide reports whether the side of a cell at line[i] is open.
+func asciiDrawSide(line string, i, lineNumber int) (bool, error) {
+	switch line[i] {
+	case '_':
+		return true, nil
+	case '|':
+		return false, nil
+	default:
+		return false, &ParseError{Line: lineNumber, Col: i + 1,
+			Msg: fmt.Sprintf("unexpected %q, the sides of a cell are _ or |", line[i])}
+	}
+}
+
+// openHiddenBottoms opens the bottom of the hidden cells when it is the only way to join them to the
+// cell below, which gives back the original passages for the mazes this package generates.
+func openHiddenBottoms(rows, cols int, passages [][]uint8, hidden []CellIndex) {
+	if len(hidden) == 0 {
+		return
+	}
+
+	sets := newDisjointSet(rows * cols)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if passages[r][c]&passageRight != 0 {
+				sets.union(r*cols+c, r*cols+c+1)
+			}
+
+			if passages[r][c]&passageBottom != 0 {
+				sets.union(r*cols+c, (r+1)*cols+c)
+			}
+		}
+	}
+
+	for _, h := range hidden {
+		below := CellIndex{Row: h.Row + 1, Col: h.Col}
+		if sets.union(h.GetID(cols), below.GetID(cols)) {
+			passages[h.Row][h.Col] |= passageBottom
+			passages[below.Row][below.Col] |= passageTop
+		}
+	}
+}
+
+func fromParsedPassages(rows, cols int, passages [][]uint8, start, end CellIndex) (*Maze, error) {
+	if start.Row < 0 {
+		return nil, &ParseError{Msg: "maze has no start marked with S"}
+	}
+
+	if end.Row < 0 {
+		return nil, &ParseError{Msg: "maze has no end marked with E"}
+	}
+
+	m, err := fromPassages(rows, cols, passages, start, end)
+	if err != nil {
+		return nil, &ParseError{Msg: err.Error()}
+	}
+
+	return m, nil
+}
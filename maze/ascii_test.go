@@ -0,0 +1,200 @@
+package maze
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseASCII(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		start CellIndex
+		end   CellIndex
+	}{
+		{"box", []string{
+			"+--+--+--+",
+			"|S    |  |",
+			"+--+  +  +",
+			"|       E|",
+			"+--+--+--+",
+		}, CellIndex{Row: 0, Col: 0}, CellIndex{Row: 1, Col: 2}},
+		{"wide cells and blank lines", []string{
+			"",
+			"+---+---+",
+			"|  E    |",
+			"+   +---+",
+			"|     S |",
+			"+---+---+",
+			"",
+		}, CellIndex{Row: 1, Col: 1}, CellIndex{Row: 0, Col: 0}},
+		{"start and end in one cell", []string{
+			"+--+",
+			"|SE|",
+			"+--+",
+		}, CellIndex{}, CellIndex{}},
+		{"AsciiDraw", []string{
+			"_________",
+			"|S_____ |",
+			"|______E|",
+		}, CellIndex{Row: 0, Col: 0}, CellIndex{Row: 1, Col: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseASCII(strings.NewReader(strings.Join(tt.lines, "\n")))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if m.Start != tt.start || m.End != tt.end {
+				t.Fatalf("got start %+v and end %+v, want %+v and %+v", m.Start, m.End, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestParseASCIIErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		line  int
+		col   int
+	}{
+		{"not a maze", []string{"maze"}, 1, 1},
+		{"empty", []string{"", " "}, 0, 0},
+		{"no cells", []string{"++"}, 1, 1},
+		{"ragged top wall", []string{
+			"+--+--+-",
+			"|S   E|",
+			"+--+--+",
+		}, 1, 8},
+		{"line longer than the top wall", []string{
+			"+--+--+",
+			"|S    E|",
+			"+--+--+",
+		}, 2, 8},
+		{"no bottom wall", []string{
+			"+--+--+",
+			"|S   E|",
+		}, 2, 1},
+		{"broken wall", []string{
+			"+--+--+",
+			"|S    |",
+			"+- +  +",
+			"|    E|",
+			"+--+--+",
+		}, 3, 2},
+		{"bad corner", []string{
+			"+--+--+",
+			"|S    |",
+			"+--|  +",
+			"|    E|",
+			"+--+--+",
+		}, 3, 4},
+		{"bad corner after blank lines", []string{
+			"",
+			"",
+			"+--+--+",
+			"|S    |",
+			"+--+  +",
+			"|    E|",
+			"+--+--x",
+		}, 7, 7},
+		{"opens at the top", []string{
+			"+--+  +",
+			"|S   E|",
+			"+--+--+",
+		}, 1, 5},
+		{"opens at the side", []string{
+			"+--+--+",
+			" S   E|",
+			"+--+--+",
+		}, 2, 1},
+		{"no right wall", []string{
+			"+--+--+",
+			"|S   E",
+			"+--+--+",
+		}, 2, 7},
+		{"bad wall between cells", []string{
+			"+--+--+",
+			"|S #E |",
+			"+--+--+",
+		}, 2, 4},
+		{"bad character in a cell", []string{
+			"+--+--+",
+			"|S   x|",
+			"+--+--+",
+		}, 2, 6},
+		{"second start", []string{
+			"+--+--+",
+			"|S   S|",
+			"+--+--+",
+		}, 2, 6},
+		{"second end", []string{
+			"+--+--+",
+			"|SE  E|",
+			"+--+--+",
+		}, 2, 6},
+		{"no start", []string{
+			"+--+--+",
+			"|    E|",
+			"+--+--+",
+		}, 0, 0},
+		{"no end", []string{
+			"+--+--+",
+			"|S    |",
+			"+--+--+",
+		}, 0, 0},
+		{"AsciiDraw ragged top wall", []string{
+			"_____",
+			"|S__E|",
+		}, 1, 1},
+		{"AsciiDraw ragged line", []string{
+			"______",
+			"|S__E",
+		}, 2, 1},
+		{"AsciiDraw walls disagree", []string{
+			"______",
+			"|S_|E|",
+		}, 2, 4},
+		{"AsciiDraw bad side", []string{
+			"______",
+			"|S__Ex",
+		}, 2, 6},
+		{"AsciiDraw bad bottom", []string{
+			"______",
+			"|S__x|",
+			"|____|",
+		}, 2, 5},
+		{"AsciiDraw opens outside", []string{
+			"______",
+			"_S__E|",
+		}, 2, 1},
+		{"AsciiDraw second start", []string{
+			"______",
+			"|S__S|",
+			"|__|E|",
+		}, 2, 5},
+		{"AsciiDraw no end", []string{
+			"______",
+			"|S___|",
+		}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseASCII(strings.NewReader(strings.Join(tt.lines, "\n")))
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("got error %v, want a *ParseError", err)
+			}
+
+			if parseErr.Line != tt.line || parseErr.Col != tt.col {
+				t.Fatalf("got error at line %d column %d, want line %d column %d: %v", parseErr.Line,
+					parseErr.Col, tt.line, tt.col, err)
+			}
+		})
+	}
+}
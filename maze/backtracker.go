@@ -0,0 +1,35 @@
+package maze
+
+import "math/rand"
+
+// RecursiveBacktracker generates mazes with a randomized depth first search.
+// It walks as far as it can before backtracking, which gives long winding
+// corridors and comparatively few dead ends.
+type RecursiveBacktracker struct{}
+
+func (RecursiveBacktracker) Generate(m *Maze, r *rand.Rand, step StepFunc) {
+	start := CellIndex{
+		Row: r.Intn(m.Rows),
+		Col: r.Intn(m.Cols),
+	}
+
+	m.Cells[start.Row][start.Col].In = true
+
+	// the stack is kept explicitly as big mazes would overflow the goroutine stack
+	stack := []CellIndex{start}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		unvisited := m.getFrontierCell(current.Row, current.Col)
+		if len(unvisited) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		next := unvisited[r.Intn(len(unvisited))]
+		m.link(current, next)
+		stack = append(stack, next)
+		step.notify(m, current, next, func() []CellIndex {
+			return stack
+		})
+	}
+}
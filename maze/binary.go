@@ -0,0 +1,348 @@
+package maze
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// BinaryVersion is the version of the binary format written by WriteBinary.
+const BinaryVersion = 1
+
+// binaryFlagCosts is set in the flags of the header when the cells are followed by a byte with the cost of
+// every cell.
+const binaryFlagCosts = 1
+
+// binaryMagic starts every binary maze.
+var binaryMagic = [4]byte{'G', 'M', 'Z', 'B'}
+
+// maxBinaryCells and maxBinaryCols cap the size of the mazes ReadBinary accepts
+// so a corrupt header cannot make it allocate the whole memory of the machine,
+// there is room for a 10000x10000 maze and for a single row of a million cells.
+const (
+	maxBinaryCells = 1 << 27
+	maxBinaryCols  = 1 << 20
+)
+
+var (
+	// ErrBadMagic is returned when the data is not a binary maze at all.
+	ErrBadMagic = errors.New("not a binary maze")
+	// ErrUnsupportedVersion is returned for binary mazes written by a newer version of the format.
+	ErrUnsupportedVersion = errors.New("unsupported binary maze version")
+	// ErrChecksum is returned when the checksum does not match the contents, the data has been corrupted.
+	ErrChecksum = errors.New("binary maze checksum mismatch")
+)
+
+// FormatError is returned when a binary maze has a valid checksum but describes a maze that cannot exist.
+type FormatError struct {
+	Reason string
+}
+
+func (e *FormatError) Error() string {
+	return "invalid binary maze: " + e.Reason
+}
+
+// binaryHeader is written in big endian right after the magic.
+type binaryHeader struct {
+	Version  uint8
+	Flags    uint8
+	Rows     uint32
+	Cols     uint32
+	StartRow uint32
+	StartCol uint32
+	EndRow   uint32
+	EndCol   uint32
+	Seed     int64
+}
+
+// WriteBinary writes the maze to w in a compact binary format. After a header with
+// the dimensions, start, end and seed every cell takes two bits, one for a passage to
+// the right and one for a passage down, which is all that is needed as the top and
+// left of a cell are the bottom and right of its neighbours. Weighted mazes follow the
+// cells with a byte per cell holding its cost. A CRC32 of everything before it closes
+// the data.
+func (m *Maze) WriteBinary(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	crc := crc32.NewIEEE()
+	out := io.MultiWriter(bw, crc)
+
+	_, err := out.Write(binaryMagic[:])
+	if err != nil {
+		return err
+	}
+
+	var flags uint8
+	if m.Weighted() {
+		flags |= binaryFlagCosts
+	}
+
+	err = binary.Write(out, binary.BigEndian, binaryHeader{
+		Version:  BinaryVersion,
+		Flags:    flags,
+		Rows:     uint32(m.Rows),
+		Cols:     uint32(m.Cols),
+		StartRow: uint32(m.Start.Row),
+		StartCol: uint32(m.Start.Col),
+		EndRow:   uint32(m.End.Row),
+		EndCol:   uint32(m.End.Col),
+		Seed:     m.Seed,
+	})
+	if err != nil {
+		return err
+	}
+
+	var current byte
+	var bits uint
+	for r := range m.Cells {
+		for c := range m.Cells[r] {
+			if m.Cells[r][c].Right {
+				current |= 1 << bits
+			}
+
+			if m.Cells[r][c].Bottom {
+				current |= 2 << bits
+			}
+
+			bits += 2
+			if bits == 8 {
+				_, err = out.Write([]byte{current})
+				if err != nil {
+					return err
+				}
+
+				current, bits = 0, 0
+			}
+		}
+	}
+
+	if bits != 0 {
+		_, err = out.Write([]byte{current})
+		if err != nil {
+			return err
+		}
+	}
+
+	if flags&binaryFlagCosts != 0 {
+		row := make([]byte, m.Cols)
+		for r := range m.Cells {
+			for c := range m.Cells[r] {
+				row[c] = uint8(m.Cost(CellIndex{Row: r, Col: c}))
+			}
+
+			_, err = out.Write(row)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = binary.Write(bw, binary.BigEndian, crc.Sum32())
+	if err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// ReadBinary reads a maze written by WriteBinary. Data that is not a binary maze gives
+// ErrBadMagic, corrupted data ErrChecksum and impossible mazes a *FormatError.
+func ReadBinary(r io.Reader) (*Maze, error) {
+	// r is not buffered so nothing after the maze is consumed, the cells are read in chunks instead
+	crc := crc32.NewIEEE()
+	in := io.TeeReader(r, crc)
+
+	var magic [4]byte
+	_, err := io.ReadFull(in, magic[:])
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	if magic != binaryMagic {
+		return nil, ErrBadMagic
+	}
+
+	var header binaryHeader
+	err = binary.Read(in, binary.BigEndian, &header)
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	if header.Version != BinaryVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, header.Version)
+	}
+
+	if header.Flags&^binaryFlagCosts != 0 {
+		return nil, &FormatError{Reason: fmt.Sprintf("unknown flags %#x", header.Flags)}
+	}
+
+	rows, cols := int(header.Rows), int(header.Cols)
+	if rows < 1 || cols < 1 || cols > maxBinaryCols || uint64(header.Rows)*uint64(header.Cols) > maxBinaryCells {
+		return nil, &FormatError{Reason: fmt.Sprintf("bad dimensions %dx%d", rows, cols)}
+	}
+
+	m := &Maze{
+		Rows: rows,
+		Cols: cols,
+		Start: CellIndex{
+			Row: int(header.StartRow),
+			Col: int(header.StartCol),
+		},
+		End: CellIndex{
+			Row: int(header.EndRow),
+			Col: int(header.EndCol),
+		},
+		Seed: header.Seed,
+		// rows are only allocated as they are read so a corrupt header cannot make us allocate
+		// a huge maze before running out of data, see readBinaryCells
+		Cells: make([][]Cell, 0),
+	}
+
+	err = m.readBinaryCells(in)
+	if err != nil {
+		return nil, err
+	}
+
+	if header.Flags&binaryFlagCosts != 0 {
+		err = m.readBinaryCosts(in)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = checkBinaryChecksum(in, crc)
+	if err != nil {
+		return nil, err
+	}
+
+	// only check the contents once the checksum is known to be right so corrupted data
+	// is always reported as such
+	if !m.inside(m.Start) || !m.inside(m.End) {
+		return nil, &FormatError{Reason: fmt.Sprintf("start %+v or end %+v outside the maze", m.Start, m.End)}
+	}
+
+	for r := 0; r < rows; r++ {
+		if m.Cells[r][cols-1].Right {
+			return nil, &FormatError{Reason: fmt.Sprintf("row %d opens to the right of the maze", r)}
+		}
+	}
+
+	for c := 0; c < cols; c++ {
+		if m.Cells[rows-1][c].Bottom {
+			return nil, &FormatError{Reason: fmt.Sprintf("column %d opens below the maze", c)}
+		}
+	}
+
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if header.Flags&binaryFlagCosts != 0 && m.Cells[r][c].Cost == 0 {
+				return nil, &FormatError{Reason: fmt.Sprintf("cell at row %d column %d costs 0", r, c)}
+			}
+
+			m.Cells[r][c].Top = r > 0 && m.Cells[r-1][c].Bottom
+			m.Cells[r][c].Left = c > 0 && m.Cells[r][c-1].Right
+			m.Cells[r][c].In = true
+		}
+	}
+
+	m.Cells[m.Start.Row][m.Start.Col].Start = true
+	m.Cells[m.End.Row][m.End.Col].End = true
+	return m, nil
+}
+
+// readBinaryCells reads the passages of every cell, two bits each, in chunks of 64KB. Each row grows as its
+// cells are read rather than being allocated whole, so no more is allocated than the data read holds.
+func (m *Maze) readBinaryCells(in io.Reader) error {
+	remaining := (uint64(m.Rows)*uint64(m.Cols) + 3) / 4
+	buf := make([]byte, 0, 64*1024)
+	var next int
+	var bits uint = 8
+	for r := 0; r < m.Rows; r++ {
+		row := make([]Cell, 0, min(m.Cols, 4*cap(buf)))
+		for c := 0; c < m.Cols; c++ {
+			if bits == 8 {
+				next++
+				bits = 0
+			}
+
+			if next >= len(buf) {
+				n := uint64(cap(buf))
+				if remaining < n {
+					n = remaining
+				}
+
+				buf = buf[:n]
+				_, err := io.ReadFull(in, buf)
+				if err != nil {
+					return unexpectedEOF(err)
+				}
+
+				remaining -= n
+				next = 0
+			}
+
+			row = append(row, Cell{
+				Right:  buf[next]&(1<<bits) != 0,
+				Bottom: buf[next]&(2<<bits) != 0,
+			})
+			bits += 2
+		}
+
+		m.Cells = append(m.Cells, row)
+	}
+
+	return nil
+}
+
+// readBinaryCosts reads the cost of every cell, a byte each, in chunks of at most a row.
+func (m *Maze) readBinaryCosts(in io.Reader) error {
+	buf := make([]byte, 64*1024)
+	for r := range m.Cells {
+		for c := 0; c < m.Cols; c += len(buf) {
+			chunk := buf
+			if m.Cols-c < len(chunk) {
+				chunk = chunk[:m.Cols-c]
+			}
+
+			_, err := io.ReadFull(in, chunk)
+			if err != nil {
+				return unexpectedEOF(err)
+			}
+
+			for i, cost := range chunk {
+				m.Cells[r][c+i].Cost = cost
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkBinaryChecksum(in io.Reader, crc hash.Hash32) error {
+	// the checksum covers everything read so far, take it before reading the stored one
+	want := crc.Sum32()
+
+	var got uint32
+	err := binary.Read(in, binary.BigEndian, &got)
+	if err != nil {
+		return unexpectedEOF(err)
+	}
+
+	if got != want {
+		return ErrChecksum
+	}
+
+	return nil
+}
+
+// unexpectedEOF turns running out of data half way through a maze into io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return fmt.Errorf("truncated binary maze: %w", io.ErrUnexpectedEOF)
+	}
+
+	return err
+}
@@ -0,0 +1,154 @@
+package maze
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"testing"
+)
+
+// binaryData returns m written with WriteBinary.
+func binaryData(t *testing.T, m *Maze) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	err := m.WriteBinary(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// resum replaces the checksum closing data with the right one for the rest of it.
+func resum(data []byte) []byte {
+	binary.BigEndian.PutUint32(data[len(data)-4:], crc32.ChecksumIEEE(data[:len(data)-4]))
+	return data
+}
+
+// binaryHeaderData returns the magic and header of a binary maze of rows by cols with nothing after it.
+func binaryHeaderData(t *testing.T, rows, cols uint32) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.Write(binaryMagic[:])
+	err := binary.Write(&buf, binary.BigEndian, binaryHeader{Version: BinaryVersion, Rows: rows, Cols: cols})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	mazes := map[string]*Maze{
+		"perfect":  NewMaze(30, 40, WithSeed(3)),
+		"weighted": NewMaze(30, 40, WithSeed(3), WithBraid(0.5), WithRandomCosts(MaxCost)),
+		"1x1":      NewMaze(1, 1, WithSeed(3)),
+		"1x500":    NewMaze(1, 500, WithSeed(3)),
+		"500x3":    NewMaze(500, 3, WithSeed(3)),
+	}
+
+	for name, m := range mazes {
+		t.Run(name, func(t *testing.T) {
+			data := binaryData(t, m)
+			// anything after the maze is left unread
+			r := bytes.NewReader(append(data, 'x'))
+			got, err := ReadBinary(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sameMaze(t, m, got)
+			if got.Seed != m.Seed || r.Len() != 1 {
+				t.Fatalf("got seed %d leaving %d bytes, want seed %d leaving 1", got.Seed, r.Len(), m.Seed)
+			}
+
+			for r := range m.Cells {
+				for c := range m.Cells[r] {
+					if got.Cost(CellIndex{Row: r, Col: c}) != m.Cost(CellIndex{Row: r, Col: c}) {
+						t.Fatalf("cell at row %d column %d costs %d, want %d", r, c,
+							got.Cost(CellIndex{Row: r, Col: c}), m.Cost(CellIndex{Row: r, Col: c}))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestReadBinaryErrors(t *testing.T) {
+	m := NewMaze(20, 30, WithSeed(5), WithRandomCosts(9))
+	corrupt := func(f func(data []byte) []byte) []byte {
+		return f(binaryData(t, m))
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"empty", nil, io.ErrUnexpectedEOF},
+		{"json", []byte(`{"rows": 20, "cols": 30}`), ErrBadMagic},
+		{"version", corrupt(func(data []byte) []byte { data[4] = BinaryVersion + 1; return data }),
+			ErrUnsupportedVersion},
+		{"cells flipped", corrupt(func(data []byte) []byte { data[50] ^= 1; return data }), ErrChecksum},
+		{"costs flipped", corrupt(func(data []byte) []byte { data[len(data)-10] ^= 4; return data }), ErrChecksum},
+		{"checksum flipped", corrupt(func(data []byte) []byte { data[len(data)-1] ^= 1; return data }),
+			ErrChecksum},
+		{"truncated header", corrupt(func(data []byte) []byte { return data[:20] }), io.ErrUnexpectedEOF},
+		{"truncated cells", corrupt(func(data []byte) []byte { return data[:100] }), io.ErrUnexpectedEOF},
+		{"truncated costs", corrupt(func(data []byte) []byte { return data[:len(data)-100] }), io.ErrUnexpectedEOF},
+		{"truncated checksum", corrupt(func(data []byte) []byte { return data[:len(data)-2] }),
+			io.ErrUnexpectedEOF},
+		// a hostile header must fail without allocating the maze it claims to be
+		{"huge rows", binaryHeaderData(t, 1<<27, 1), io.ErrUnexpectedEOF},
+		{"huge row", binaryHeaderData(t, 1, 1<<20), io.ErrUnexpectedEOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadBinary(bytes.NewReader(tt.data))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadBinaryFormatErrors(t *testing.T) {
+	m := NewMaze(20, 30, WithSeed(5), WithRandomCosts(9))
+	// the header starts after the magic, the rows are at 6, the columns at 10 and the start row at 14
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"no rows", binaryHeaderData(t, 0, 30)},
+		{"no columns", binaryHeaderData(t, 20, 0)},
+		{"hostile columns", binaryHeaderData(t, 4, 0xFFFFFFFF)},
+		{"too many columns", binaryHeaderData(t, 1, maxBinaryCols+1)},
+		{"too many cells", binaryHeaderData(t, 1<<14, 1<<14)},
+		{"flags", func() []byte { data := binaryData(t, m); data[5] |= 2; return resum(data) }()},
+		{"start outside", func() []byte {
+			data := binaryData(t, m)
+			binary.BigEndian.PutUint32(data[14:], 20)
+			return resum(data)
+		}()},
+		{"open right border", func() []byte {
+			data := binaryData(t, m)
+			// the last cell of the first row is the 30th, the lowest bit of its pair opens it to the right
+			data[4+binary.Size(binaryHeader{})+29/4] |= 1 << (29 % 4 * 2)
+			return resum(data)
+		}()},
+		{"free cell", func() []byte { data := binaryData(t, m); data[len(data)-5] = 0; return resum(data) }()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadBinary(bytes.NewReader(tt.data))
+			var formatErr *FormatError
+			if !errors.As(err, &formatErr) {
+				t.Fatalf("got error %v, want a *FormatError", err)
+			}
+		})
+	}
+}
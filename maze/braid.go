@@ -0,0 +1,109 @@
+package maze
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Braid removes around fraction of the dead ends of the maze, between 0 and 1, by knocking down one of their
+// walls. Walls into other dead ends are preferred so one wall removes two of them. The maze is left with loops
+// so there is more than one way from the start to the end. Every wall removed is reported to step, which may
+// be nil.
+func (m *Maze) Braid(fraction float64, r *rand.Rand, step StepFunc) {
+	var deadEnds []CellIndex
+	for row := range m.Cells {
+		for col := range m.Cells[row] {
+			if m.Cells[row][col].openings() == 1 {
+				deadEnds = append(deadEnds, CellIndex{Row: row, Col: col})
+			}
+		}
+	}
+
+	r.Shuffle(len(deadEnds), func(i, j int) {
+		deadEnds[i], deadEnds[j] = deadEnds[j], deadEnds[i]
+	})
+
+	for _, c := range deadEnds {
+		// knocking down an earlier wall can have opened this one already
+		if m.Cells[c.Row][c.Col].openings() != 1 || r.Float64() >= fraction {
+			continue
+		}
+
+		walled := m.walledNeighbours(c)
+		if len(walled) == 0 {
+			continue
+		}
+
+		var joinDeadEnds []CellIndex
+		for _, n := range walled {
+			if m.Cells[n.Row][n.Col].openings() == 1 {
+				joinDeadEnds = append(joinDeadEnds, n)
+			}
+		}
+
+		if len(joinDeadEnds) > 0 {
+			walled = joinDeadEnds
+		}
+
+		to := walled[r.Intn(len(walled))]
+		m.link(c, to)
+		step.notify(m, c, to, nil)
+	}
+}
+
+// AddPassages knocks down around fraction of the walls between cells that are left, between 0 and 1, at
+// random. Every wall removed is reported to step, which may be nil.
+func (m *Maze) AddPassages(fraction float64, r *rand.Rand, step StepFunc) {
+	type wall struct {
+		a, b CellIndex
+	}
+
+	var walls []wall
+	for row := range m.Cells {
+		for col := range m.Cells[row] {
+			c := CellIndex{Row: row, Col: col}
+			if col+1 < m.Cols && !m.Cells[row][col].Right {
+				walls = append(walls, wall{c, CellIndex{Row: row, Col: col + 1}})
+			}
+
+			if row+1 < m.Rows && !m.Cells[row][col].Bottom {
+				walls = append(walls, wall{c, CellIndex{Row: row + 1, Col: col}})
+			}
+		}
+	}
+
+	r.Shuffle(len(walls), func(i, j int) {
+		walls[i], walls[j] = walls[j], walls[i]
+	})
+
+	n := int(math.Round(fraction * float64(len(walls))))
+	if n > len(walls) {
+		n = len(walls)
+	}
+
+	for i := 0; i < n; i++ {
+		w := walls[i]
+		m.link(w.a, w.b)
+		step.notify(m, w.a, w.b, nil)
+	}
+}
+
+// walledNeighbours returns the cells next to c that a wall separates it from.
+func (m *Maze) walledNeighbours(c CellIndex) []CellIndex {
+	open := m.openNeighbours(c)
+	walled := make([]CellIndex, 0, 4)
+	for _, n := range m.neighbours(c.Row, c.Col) {
+		isOpen := false
+		for _, o := range open {
+			if n == o {
+				isOpen = true
+			}
+		}
+
+		if !isOpen {
+			walled = append(walled, n)
+		}
+	}
+
+	return walled
+}
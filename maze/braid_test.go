@@ -0,0 +1,106 @@
+package maze
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// walls returns the number of walls between the cells of m.
+func walls(m *Maze) int {
+	return m.Rows*(m.Cols-1) + (m.Rows-1)*m.Cols - countPassages(m)
+}
+
+// countPassages returns the number of passages between the cells of m.
+func countPassages(m *Maze) int {
+	var passages int
+	for r := range m.Cells {
+		for c := range m.Cells[r] {
+			if m.Cells[r][c].Right {
+				passages++
+			}
+
+			if m.Cells[r][c].Bottom {
+				passages++
+			}
+		}
+	}
+
+	return passages
+}
+
+// countDeadEnds returns the number of cells of m with a single opening.
+func countDeadEnds(m *Maze) int {
+	var deadEnds int
+	for r := range m.Cells {
+		for c := range m.Cells[r] {
+			if m.Cells[r][c].openings() == 1 {
+				deadEnds++
+			}
+		}
+	}
+
+	return deadEnds
+}
+
+func TestBraid(t *testing.T) {
+	forEachGenerator(t, [][2]int{{1, 30}, {30, 1}, {12, 17}, {40, 40}}, func(t *testing.T, m *Maze) {
+		deadEnds, passages := countDeadEnds(m), countPassages(m)
+		var removed int
+		m.Braid(0, rand.New(rand.NewSource(1)), nil)
+		m.Braid(1, rand.New(rand.NewSource(1)), func(*Maze, Step) {
+			removed++
+		})
+
+		checkConnected(t, m)
+		if got := countPassages(m); got != passages+removed {
+			t.Fatalf("maze has %d passages after reporting %d walls removed from %d", got, removed, passages)
+		}
+
+		// a wall removed takes away one or two dead ends
+		if removed > deadEnds || deadEnds-countDeadEnds(m) < removed {
+			t.Fatalf("removed %d walls for %d dead ends", removed, deadEnds)
+		}
+
+		// the ends of a single corridor have no wall to knock down, every other dead end is gone
+		for r := range m.Cells {
+			for c := range m.Cells[r] {
+				cell := CellIndex{Row: r, Col: c}
+				if m.Cells[r][c].openings() == 1 && len(m.walledNeighbours(cell)) > 0 {
+					t.Fatalf("cell at row %d column %d is still a dead end", r, c)
+				}
+			}
+		}
+	})
+}
+
+func TestBraidFraction(t *testing.T) {
+	forEachGenerator(t, [][2]int{{40, 40}}, func(t *testing.T, m *Maze) {
+		deadEnds := countDeadEnds(m)
+		m.Braid(0.5, rand.New(rand.NewSource(1)), nil)
+		checkConnected(t, m)
+
+		// around half the dead ends knock down a wall, taking one or two dead ends each
+		if left := countDeadEnds(m); left < deadEnds/5 || left > deadEnds*4/5 {
+			t.Fatalf("%d of %d dead ends are left", left, deadEnds)
+		}
+	})
+}
+
+func TestAddPassages(t *testing.T) {
+	for _, fraction := range []float64{0, 0.05, 0.3, 1} {
+		forEachGenerator(t, [][2]int{{1, 30}, {12, 17}, {40, 40}}, func(t *testing.T, m *Maze) {
+			before, passages := walls(m), countPassages(m)
+			var removed int
+			m.AddPassages(fraction, rand.New(rand.NewSource(1)), func(*Maze, Step) {
+				removed++
+			})
+
+			checkConnected(t, m)
+			want := int(math.Round(fraction * float64(before)))
+			if got := before - walls(m); got != want || removed != want || countPassages(m) != passages+want {
+				t.Fatalf("%d of %d walls removed and %d reported, want %d", got, before, removed, want)
+			}
+		})
+	}
+}
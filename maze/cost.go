@@ -0,0 +1,153 @@
+package maze
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+)
+
+// MaxCost is the highest cost a cell can have.
+const MaxCost = math.MaxUint8
+
+// costColour is what the costliest cells of a maze are shaded with, cheaper cells are shaded between it and
+// white.
+var costColour = color.RGBA{
+	R: 150,
+	G: 105,
+	B: 60,
+	A: 255,
+}
+
+// Cost returns the cost of moving into the cell c, cells without a cost cost 1.
+func (m *Maze) Cost(c CellIndex) int {
+	cost := int(m.Cells[c.Row][c.Col].Cost)
+	if cost == 0 {
+		return 1
+	}
+
+	return cost
+}
+
+// Weighted reports whether any cell of the maze costs more than 1 to move into.
+func (m *Maze) Weighted() bool {
+	return m.maxCost() > 1
+}
+
+// PathCost returns the cost of walking path, the sum of the costs of its cells apart from Start as the walk
+// begins there.
+func (m *Maze) PathCost(path []*CellIndex) int {
+	var cost int
+	for _, c := range path {
+		if *c != m.Start {
+			cost += m.Cost(*c)
+		}
+	}
+
+	return cost
+}
+
+func (m *Maze) maxCost() int {
+	highest := 1
+	for r := range m.Cells {
+		for c := range m.Cells[r] {
+			if cost := int(m.Cells[r][c].Cost); cost > highest {
+				highest = cost
+			}
+		}
+	}
+
+	return highest
+}
+
+// RandomCosts covers the maze in patches of terrain, like mud or water, each costing between 2 and maxCost to
+// move through. The cells left between patches cost 1.
+func (m *Maze) RandomCosts(maxCost uint8, r *rand.Rand) {
+	if maxCost < 2 {
+		return
+	}
+
+	maxRadius := m.Rows
+	if m.Cols < maxRadius {
+		maxRadius = m.Cols
+	}
+
+	maxRadius = maxRadius/6 + 1
+	patches := m.Rows*m.Cols/25 + 1
+	for i := 0; i < patches; i++ {
+		centre := CellIndex{
+			Row: r.Intn(m.Rows),
+			Col: r.Intn(m.Cols),
+		}
+
+		radius := 1 + r.Intn(maxRadius)
+		cost := uint8(2 + r.Intn(int(maxCost)-1))
+		for row := centre.Row - radius; row <= centre.Row+radius; row++ {
+			for col := centre.Col - radius; col <= centre.Col+radius; col++ {
+				dr, dc := row-centre.Row, col-centre.Col
+				if m.inside(CellIndex{Row: row, Col: col}) && dr*dr+dc*dc <= radius*radius {
+					m.Cells[row][col].Cost = cost
+				}
+			}
+		}
+	}
+}
+
+// CostsFromImage sets the cost of every cell from the part of img over it when img is stretched over the whole
+// maze. White costs 1, black maxCost and greys in between.
+func (m *Maze) CostsFromImage(img image.Image, maxCost uint8) {
+	if maxCost < 1 {
+		maxCost = 1
+	}
+
+	bounds := img.Bounds()
+	for row := range m.Cells {
+		y0 := bounds.Min.Y + row*bounds.Dy()/m.Rows
+		y1 := bounds.Min.Y + (row+1)*bounds.Dy()/m.Rows
+		for col := range m.Cells[row] {
+			x0 := bounds.Min.X + col*bounds.Dx()/m.Cols
+			x1 := bounds.Min.X + (col+1)*bounds.Dx()/m.Cols
+
+			// images smaller than the maze give some cells no pixels, those take the nearest one
+			if y1 == y0 {
+				y1 = y0 + 1
+			}
+
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+
+			var sum, n float64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					sum += float64(color.Gray16Model.Convert(img.At(x, y)).(color.Gray16).Y)
+					n++
+				}
+			}
+
+			darkness := 1 - sum/n/0xffff
+			m.Cells[row][col].Cost = uint8(1 + math.Round(darkness*float64(maxCost-1)))
+		}
+	}
+}
+
+// cellColour returns the colour a cell that is neither the start nor the end is drawn in, shaded by its cost
+// relative to the costliest cell of the maze, which is maxCost.
+func (m *Maze) cellColour(c CellIndex, maxCost int) color.Color {
+	cost := m.Cost(c)
+	if cost <= 1 {
+		return color.White
+	}
+
+	t := float64(cost-1) / float64(maxCost-1)
+	shade := func(to uint8) uint8 {
+		return uint8(math.Round(255 - t*(255-float64(to))))
+	}
+
+	return color.RGBA{
+		R: shade(costColour.R),
+		G: shade(costColour.G),
+		B: shade(costColour.B),
+		A: 255,
+	}
+}
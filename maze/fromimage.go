@@ -0,0 +1,476 @@
+package maze
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// ImageOptions tells FromImage how the maze is drawn, the zero value works for images made by Maze.Image.
+type ImageOptions struct {
+	// CellSize is the width in pixels of a cell, 0 detects it from the image.
+	CellSize int
+	// Pitch is the distance in pixels from one cell to the next, a cell and a wall, 0 detects it from
+	// the image.
+	Pitch int
+	// StartColour and EndColour are the colours the start and end cells are painted in, by default the
+	// red and green used by Maze.Image.
+	StartColour color.Color
+	EndColour   color.Color
+	// Start and End are used when no cell has the start or end colour.
+	Start *CellIndex
+	End   *CellIndex
+}
+
+// FromImage reads a maze from a picture of dark walls and light passages laid out on a regular grid of
+// square cells, like the ones made by Maze.Image. The size of the cells and walls is worked out from the
+// most common lengths of the light and dark runs of pixels unless given in opts, and every cell of the
+// grid must be mostly light. The start and end are the cells painted in the start and end colours.
+//
+// Mazes without walls between their cells, like the ones that are a single straight corridor, leave nothing
+// to check the pitch against so it has to be given, otherwise an error is returned. The cells of a corridor
+// are then lined up with the start cell.
+func FromImage(img image.Image, opts ImageOptions) (*Maze, error) {
+	if opts.StartColour == nil {
+		opts.StartColour = color.RGBA{R: 255, A: 255}
+	}
+
+	if opts.EndColour == nil {
+		opts.EndColour = color.RGBA{G: 255, A: 255}
+	}
+
+	g := newPixelGrid(img)
+	cellSize, pitch := opts.CellSize, opts.Pitch
+	if cellSize == 0 {
+		cellSize = mostCommon(g.runs(true))
+	}
+
+	if pitch == 0 {
+		pitch = cellSize + mostCommon(g.runs(false))
+	}
+
+	if cellSize < 1 || pitch <= cellSize {
+		return nil, fmt.Errorf("could not find the grid of the maze, cells of %d pixels every %d pixels, try "+
+			"giving the pitch", cellSize, pitch)
+	}
+
+	grid := imageGrid{
+		pixels:   g,
+		cellSize: cellSize,
+		pitch:    pitch,
+		x:        phase(g.bounds.Dx(), cellSize, pitch, g.openInColumn),
+		y:        phase(g.bounds.Dy(), cellSize, pitch, g.openInRow),
+	}
+
+	err := grid.findCells()
+	if err != nil {
+		return nil, err
+	}
+
+	// a maze that is one straight corridor has no walls between its cells to measure, picking the wrong
+	// pitch then finds a few cells in the middle of it
+	width, height := grid.cols*pitch-pitch+cellSize, grid.rows*pitch-pitch+cellSize
+	open := g.count(0, 0, g.bounds.Dx(), g.bounds.Dy())
+	outside := open - g.count(grid.x, grid.y, width, height)
+	if outside > 2*cellSize*cellSize && outside*20 > open {
+		return nil, fmt.Errorf("could not find the grid of the maze, cells of %d pixels every %d pixels leave "+
+			"most of it out, try giving the pitch", cellSize, pitch)
+	}
+
+	passages, walls, err := grid.readPassages()
+	if err != nil {
+		return nil, err
+	}
+
+	// without a wall between two cells any pitch and offset fit, a corridor read with the wrong pitch comes
+	// back shorter and with the wrong offset gains a cell at one end
+	if walls == 0 && opts.Pitch == 0 {
+		return nil, fmt.Errorf("could not find the grid of the maze, no walls between its %dx%d cells confirm "+
+			"the pitch of %d pixels, try giving the pitch", grid.rows, grid.cols, pitch)
+	}
+
+	if walls == 0 {
+		err = grid.alignCorridor(img, opts.StartColour)
+		if err != nil {
+			return nil, err
+		}
+
+		passages, _, err = grid.readPassages()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	start, err := grid.findColour(img, opts.StartColour, opts.Start, "start")
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := grid.findColour(img, opts.EndColour, opts.End, "end")
+	if err != nil {
+		return nil, err
+	}
+
+	return fromPassages(grid.rows, grid.cols, passages, start, end)
+}
+
+// pixelGrid holds whether every pixel of an image is open, anything that is not dark is.
+type pixelGrid struct {
+	bounds image.Rectangle
+	open   []bool
+}
+
+func newPixelGrid(img image.Image) *pixelGrid {
+	b := img.Bounds()
+	g := &pixelGrid{
+		bounds: b,
+		open:   make([]bool, b.Dx()*b.Dy()),
+	}
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, gr, bl, _ := img.At(x, y).RGBA()
+			// a channel over half way makes the pixel light, so the coloured start and end are open too
+			g.open[(y-b.Min.Y)*b.Dx()+x-b.Min.X] = r >= 0x8000 || gr >= 0x8000 || bl >= 0x8000
+		}
+	}
+
+	return g
+}
+
+// at reports whether the pixel at x, y counted from the corner of the image is open.
+func (g *pixelGrid) at(x, y int) bool {
+	return g.open[y*g.bounds.Dx()+x]
+}
+
+// runs returns the lengths of the runs of open or dark pixels along every row and column, leaving out
+// the ones touching the edges as those may be cut short.
+func (g *pixelGrid) runs(open bool) []int {
+	w, h := g.bounds.Dx(), g.bounds.Dy()
+	runs := make([]int, 0)
+	collect := func(n int, at func(i int) bool) {
+		start := -1
+		for i := 0; i < n; i++ {
+			if at(i) != open {
+				if start > 0 {
+					runs = append(runs, i-start)
+				}
+
+				start = -1
+				continue
+			}
+
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+
+	for y := 0; y < h; y++ {
+		collect(w, func(x int) bool {
+			return g.at(x, y)
+		})
+	}
+
+	for x := 0; x < w; x++ {
+		collect(h, func(y int) bool {
+			return g.at(x, y)
+		})
+	}
+
+	return runs
+}
+
+func (g *pixelGrid) openInColumn(x int) int {
+	var n int
+	for y := 0; y < g.bounds.Dy(); y++ {
+		if g.at(x, y) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (g *pixelGrid) openInRow(y int) int {
+	var n int
+	for x := 0; x < g.bounds.Dx(); x++ {
+		if g.at(x, y) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// phase returns the offset in [0, pitch) where cells start along an axis of n pixels, the one whose cells
+// take in the most open pixels.
+func phase(n, cellSize, pitch int, openIn func(int) int) int {
+	counts := make([]int, n)
+	for i := range counts {
+		counts[i] = openIn(i)
+	}
+
+	best, bestScore := 0, -1
+	for offset := 0; offset < pitch; offset++ {
+		var score int
+		for i := range counts {
+			if ((i-offset)%pitch+pitch)%pitch < cellSize {
+				score += counts[i]
+			}
+		}
+
+		if score > bestScore {
+			best, bestScore = offset, score
+		}
+	}
+
+	return best
+}
+
+// mostlyOpen reports whether more than half of the rectangle is open, which is forgiving enough for
+// scanned images and keeps the start and end exits drawn between cells from counting as cells.
+func (g *pixelGrid) mostlyOpen(x, y, w, h int) bool {
+	if x < 0 || y < 0 || x+w > g.bounds.Dx() || y+h > g.bounds.Dy() {
+		return false
+	}
+
+	return g.count(x, y, w, h)*2 > w*h
+}
+
+// count returns how many pixels of the rectangle are open.
+func (g *pixelGrid) count(x, y, w, h int) int {
+	var open int
+	for yi := y; yi < y+h; yi++ {
+		for xi := x; xi < x+w; xi++ {
+			if g.at(xi, yi) {
+				open++
+			}
+		}
+	}
+
+	return open
+}
+
+// imageGrid is where the cells of the maze are in an image.
+type imageGrid struct {
+	pixels   *pixelGrid
+	cellSize int
+	pitch    int
+	// x and y are where the first cell of the maze starts
+	x    int
+	y    int
+	rows int
+	cols int
+}
+
+// findCells moves the first cell to the top left open cell of the grid and counts the rows and columns
+// up to the bottom right one.
+func (g *imageGrid) findCells() error {
+	minRow, minCol, maxRow, maxCol := -1, -1, -1, -1
+	for y, r := g.y, 0; y+g.cellSize <= g.pixels.bounds.Dy(); y, r = y+g.pitch, r+1 {
+		for x, c := g.x, 0; x+g.cellSize <= g.pixels.bounds.Dx(); x, c = x+g.pitch, c+1 {
+			if !g.pixels.mostlyOpen(x, y, g.cellSize, g.cellSize) {
+				continue
+			}
+
+			if minRow < 0 || r < minRow {
+				minRow = r
+			}
+
+			if minCol < 0 || c < minCol {
+				minCol = c
+			}
+
+			if r > maxRow {
+				maxRow = r
+			}
+
+			if c > maxCol {
+				maxCol = c
+			}
+		}
+	}
+
+	if minRow < 0 {
+		return fmt.Errorf("could not find any cell of %d pixels in the image", g.cellSize)
+	}
+
+	g.x += minCol * g.pitch
+	g.y += minRow * g.pitch
+	g.rows = maxRow - minRow + 1
+	g.cols = maxCol - minCol + 1
+	return nil
+}
+
+// readPassages returns which sides of every cell are open and how many walls there are between cells.
+func (g *imageGrid) readPassages() ([][]uint8, int, error) {
+	passages := make([][]uint8, g.rows)
+	var walls int
+	for r := range passages {
+		passages[r] = make([]uint8, g.cols)
+		for c := range passages[r] {
+			x, y := g.cellOrigin(r, c)
+			if !g.pixels.mostlyOpen(x, y, g.cellSize, g.cellSize) {
+				return nil, 0, fmt.Errorf("cell at row %d column %d is a wall, the image is not a grid of %d "+
+					"pixel cells every %d pixels", r, c, g.cellSize, g.pitch)
+			}
+
+			if c+1 < g.cols && g.pixels.mostlyOpen(x+g.cellSize, y, g.pitch-g.cellSize, g.cellSize) {
+				passages[r][c] |= passageRight
+			} else if c+1 < g.cols {
+				walls++
+			}
+
+			if c > 0 && passages[r][c-1]&passageRight != 0 {
+				passages[r][c] |= passageLeft
+			}
+
+			if r+1 < g.rows && g.pixels.mostlyOpen(x, y+g.cellSize, g.cellSize, g.pitch-g.cellSize) {
+				passages[r][c] |= passageBottom
+			} else if r+1 < g.rows {
+				walls++
+			}
+
+			if r > 0 && passages[r-1][c]&passageBottom != 0 {
+				passages[r][c] |= passageTop
+			}
+		}
+	}
+
+	return passages, walls, nil
+}
+
+// alignCorridor lines the cells of a maze that is a single row or column up with its start cell, as along a
+// corridor they fit at any offset. Maze.Image paints the exit of the start cell in the same colour right
+// next to it, when the coloured run is longer than a cell the cell is the end of it away from the outside.
+func (g *imageGrid) alignCorridor(img image.Image, colour color.Color) error {
+	horizontal := g.rows == 1
+	if g.rows > 1 && g.cols > 1 || g.rows == g.cols {
+		return nil
+	}
+
+	// without a start colour there is nothing better to go by
+	start, err := g.findColour(img, colour, nil, "start")
+	if err != nil {
+		return nil
+	}
+
+	// pixel returns the coordinates of the pixel i along the corridor through the middle of the start cell
+	x, y := g.cellOrigin(start.Row, start.Col)
+	n, found := g.pixels.bounds.Dy(), y
+	pixel := func(i int) (int, int) {
+		return x + g.cellSize/2, i
+	}
+
+	if horizontal {
+		n, found = g.pixels.bounds.Dx(), x
+		pixel = func(i int) (int, int) {
+			return i, y + g.cellSize/2
+		}
+	}
+
+	coloured := func(i int) bool {
+		px, py := pixel(i)
+		return i >= 0 && i < n &&
+			similarColour(img.At(g.pixels.bounds.Min.X+px, g.pixels.bounds.Min.Y+py), colour)
+	}
+
+	from, to := found, found
+	for coloured(from - 1) {
+		from--
+	}
+
+	for coloured(to) {
+		to++
+	}
+
+	cell := from
+	if px, py := pixel(from - 1); to-from > g.cellSize && (from == 0 || !g.pixels.at(px, py)) {
+		cell = to - g.cellSize
+	}
+
+	if cell == found {
+		return nil
+	}
+
+	offset := ((cell-found)%g.pitch + g.pitch) % g.pitch
+	if horizontal {
+		g.x = (g.x + offset) % g.pitch
+	} else {
+		g.y = (g.y + offset) % g.pitch
+	}
+
+	return g.findCells()
+}
+
+// cellOrigin returns the top left pixel of a cell counted from the corner of the image.
+func (g *imageGrid) cellOrigin(row, col int) (int, int) {
+	return g.x + col*g.pitch, g.y + row*g.pitch
+}
+
+// findColour returns the cell mostly painted in c, or fallback when there is none.
+func (g *imageGrid) findColour(img image.Image, c color.Color, fallback *CellIndex, name string) (CellIndex,
+	error) {
+	found := make([]CellIndex, 0, 1)
+	for r := 0; r < g.rows; r++ {
+		for col := 0; col < g.cols; col++ {
+			x, y := g.cellOrigin(r, col)
+			var matching int
+			for yi := y; yi < y+g.cellSize; yi++ {
+				for xi := x; xi < x+g.cellSize; xi++ {
+					if similarColour(img.At(g.pixels.bounds.Min.X+xi, g.pixels.bounds.Min.Y+yi), c) {
+						matching++
+					}
+				}
+			}
+
+			if matching*2 > g.cellSize*g.cellSize {
+				found = append(found, CellIndex{Row: r, Col: col})
+			}
+		}
+	}
+
+	switch {
+	case len(found) == 1:
+		return found[0], nil
+	case len(found) > 1:
+		return CellIndex{}, fmt.Errorf("%d cells have the %s colour", len(found), name)
+	case fallback != nil:
+		return *fallback, nil
+	default:
+		return CellIndex{}, fmt.Errorf("no cell has the %s colour", name)
+	}
+}
+
+// similarColour reports whether every channel of a and b is within a quarter of each other.
+func similarColour(a, b color.Color) bool {
+	ar, ag, ab, _ := a.RGBA()
+	br, bg, bb, _ := b.RGBA()
+	return closeChannel(ar, br) && closeChannel(ag, bg) && closeChannel(ab, bb)
+}
+
+func closeChannel(a, b uint32) bool {
+	if a > b {
+		return a-b < 0x4000
+	}
+
+	return b-a < 0x4000
+}
+
+// mostCommon returns the value found the most times in values, the smallest one on ties, or 0 when empty.
+func mostCommon(values []int) int {
+	counts := make(map[int]int)
+	for _, v := range values {
+		counts[v]++
+	}
+
+	best, bestCount := 0, 0
+	for v, n := range counts {
+		if n > bestCount || (n == bestCount && v < best) {
+			best, bestCount = v, n
+		}
+	}
+
+	return best
+}
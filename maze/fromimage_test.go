@@ -0,0 +1,99 @@
+package maze
+
+import (
+	"fmt"
+	"testing"
+)
+
+// imagePitch returns the distance between cells Maze.Image uses for a maze of rows by cols.
+func imagePitch(rows, cols int) int {
+	cellWidth, _, wallWidth, _, _, _ := getMeasurements(cols, rows)
+	return cellWidth + wallWidth
+}
+
+// sameMaze fails t when got does not have the size, walls, start and end of want.
+func sameMaze(t *testing.T, want, got *Maze) {
+	t.Helper()
+	if got.Rows != want.Rows || got.Cols != want.Cols {
+		t.Fatalf("got a %dx%d maze, want %dx%d", got.Rows, got.Cols, want.Rows, want.Cols)
+	}
+
+	if got.Start != want.Start || got.End != want.End {
+		t.Errorf("got start %+v end %+v, want start %+v end %+v", got.Start, got.End, want.Start, want.End)
+	}
+
+	for r := range want.Cells {
+		for c := range want.Cells[r] {
+			w, g := want.Cells[r][c], got.Cells[r][c]
+			if w.Top != g.Top || w.Bottom != g.Bottom || w.Left != g.Left || w.Right != g.Right {
+				t.Fatalf("cell at row %d column %d has passages %v %v %v %v, want %v %v %v %v", r, c,
+					g.Top, g.Right, g.Bottom, g.Left, w.Top, w.Right, w.Bottom, w.Left)
+			}
+		}
+	}
+}
+
+func TestFromImageRoundTrip(t *testing.T) {
+	sizes := [][2]int{{7, 9}, {2, 50}, {50, 2}, {3, 500}, {500, 3}, {30, 30}}
+	for _, size := range sizes {
+		for _, name := range GeneratorNames() {
+			for _, placement := range PlacementNames() {
+				size, name, placement := size, name, placement
+				t.Run(fmt.Sprintf("%dx%d/%s/%s", size[0], size[1], name, placement), func(t *testing.T) {
+					g, _ := GetGenerator(name)
+					p, _ := GetPlacement(placement)
+					want := NewMaze(size[0], size[1], WithSeed(7), WithGenerator(g), WithPlacement(p))
+					got, err := FromImage(want.ToImage(), ImageOptions{})
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					sameMaze(t, want, got)
+				})
+			}
+		}
+	}
+}
+
+func TestFromImageCorridors(t *testing.T) {
+	sizes := [][2]int{{1, 2}, {2, 1}, {1, 50}, {50, 1}, {1, 500}, {500, 1}}
+	for _, size := range sizes {
+		for _, placement := range PlacementNames() {
+			size, placement := size, placement
+			t.Run(fmt.Sprintf("%dx%d/%s", size[0], size[1], placement), func(t *testing.T) {
+				p, _ := GetPlacement(placement)
+				want := NewMaze(size[0], size[1], WithSeed(7), WithPlacement(p))
+				img := want.ToImage()
+
+				// there are no walls between the cells to tell a wrong pitch from the right one
+				got, err := FromImage(img, ImageOptions{})
+				if err == nil {
+					t.Fatalf("read a %dx%d maze without the pitch, want an error", got.Rows, got.Cols)
+				}
+
+				got, err = FromImage(img, ImageOptions{Pitch: imagePitch(size[0], size[1])})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				sameMaze(t, want, got)
+			})
+		}
+	}
+}
+
+func TestFromImageSingleCell(t *testing.T) {
+	want := NewMaze(1, 1, WithSeed(7))
+	img := want.ToImage()
+	if _, err := FromImage(img, ImageOptions{}); err == nil {
+		t.Fatal("read a single cell maze without the pitch, want an error")
+	}
+
+	// the start and end share the cell so it is only painted in the start colour
+	got, err := FromImage(img, ImageOptions{Pitch: imagePitch(1, 1), End: &want.End})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sameMaze(t, want, got)
+}
@@ -0,0 +1,134 @@
+package maze
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// Generator carves passages into a maze whose cells all start walled off.
+// When Generate returns every cell must be reachable from every other cell.
+// All random choices must be drawn from r so that mazes can be reproduced
+// from their seed. Every wall removed must be reported to step, which may
+// be nil.
+type Generator interface {
+	Generate(m *Maze, r *rand.Rand, step StepFunc)
+}
+
+// Step describes a single wall being removed while a maze is generated.
+type Step struct {
+	From CellIndex
+	To   CellIndex
+	// Frontier holds the cells the generator is working from, like the frontier set of
+	// Prim or the stack of the backtracker. It is only valid until the StepFunc returns.
+	Frontier []CellIndex
+}
+
+// StepFunc is called after every wall a Generator removes.
+type StepFunc func(m *Maze, s Step)
+
+// notify calls f when it is set, frontier is only called when f is set so generators
+// do not pay for building it when nobody is watching.
+func (f StepFunc) notify(m *Maze, from, to CellIndex, frontier func() []CellIndex) {
+	if f == nil {
+		return
+	}
+
+	s := Step{
+		From: from,
+		To:   to,
+	}
+
+	if frontier != nil {
+		s.Frontier = frontier()
+	}
+
+	f(m, s)
+}
+
+var generators = map[string]Generator{
+	"prim":          Prim{},
+	"kruskal":       Kruskal{},
+	"backtracker":   RecursiveBacktracker{},
+	"wilson":        Wilson{},
+	"aldous-broder": AldousBroder{},
+}
+
+// GetGenerator returns the generator registered under name.
+func GetGenerator(name string) (Generator, error) {
+	g, ok := generators[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown generator: %s", name)
+	}
+
+	return g, nil
+}
+
+// GeneratorNames returns the names of all the available generators sorted alphabetically.
+func GeneratorNames() []string {
+	names := make([]string, 0, len(generators))
+	for name := range generators {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// link removes the wall between two adjacent cells and marks both as part of the maze.
+func (m *Maze) link(a, b CellIndex) {
+	switch {
+	case b.Row == a.Row+1 && b.Col == a.Col:
+		m.Cells[a.Row][a.Col].Bottom = true
+		m.Cells[b.Row][b.Col].Top = true
+	case b.Row == a.Row-1 && b.Col == a.Col:
+		m.Cells[a.Row][a.Col].Top = true
+		m.Cells[b.Row][b.Col].Bottom = true
+	case b.Col == a.Col+1 && b.Row == a.Row:
+		m.Cells[a.Row][a.Col].Right = true
+		m.Cells[b.Row][b.Col].Left = true
+	case b.Col == a.Col-1 && b.Row == a.Row:
+		m.Cells[a.Row][a.Col].Left = true
+		m.Cells[b.Row][b.Col].Right = true
+	default:
+		return
+	}
+
+	m.Cells[a.Row][a.Col].In = true
+	m.Cells[b.Row][b.Col].In = true
+}
+
+// neighbours returns the cells above, below, left and right of the given cell that are inside the maze.
+func (m *Maze) neighbours(row, col int) []CellIndex {
+	indexes := []CellIndex{
+		{
+			Row: row + 1,
+			Col: col,
+		},
+		{
+			Row: row - 1,
+			Col: col,
+		},
+		{
+			Col: col - 1,
+			Row: row,
+		},
+		{
+			Col: col + 1,
+			Row: row,
+		},
+	}
+
+	neighbours := make([]CellIndex, 0, len(indexes))
+	for _, neighbour := range indexes {
+		if m.inside(neighbour) {
+			neighbours = append(neighbours, neighbour)
+		}
+	}
+
+	return neighbours
+}
+
+func (m *Maze) inside(c CellIndex) bool {
+	return c.Col >= 0 && c.Col < m.Cols && c.Row >= 0 && c.Row < m.Rows
+}
@@ -0,0 +1,137 @@
+package maze
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"os"
+)
+
+var (
+	frontierColour = color.RGBA{
+		R: 70,
+		G: 130,
+		B: 255,
+		A: 255,
+	}
+
+	// animationPalette holds every colour drawn on an animation frame, black comes first so
+	// new frames start with every wall in place
+	animationPalette = color.Palette{
+		color.Black,
+		color.White,
+		color.RGBA{
+			R: 255,
+			A: 255,
+		},
+		color.RGBA{
+			G: 255,
+			A: 255,
+		},
+		frontierColour,
+	}
+)
+
+// GenerationAnimation records frames of a maze being carved so they can be written as an animated GIF.
+// Its Step method is meant to be passed to NewMaze with WithStepFunc.
+type GenerationAnimation struct {
+	every  int
+	delay  int
+	steps  int
+	frames []*image.Paletted
+}
+
+// NewGenerationAnimation returns an animation that takes a frame every given number of walls removed and
+// shows each frame for delay hundredths of a second.
+func NewGenerationAnimation(every, delay int) *GenerationAnimation {
+	if every < 1 {
+		every = 1
+	}
+
+	return &GenerationAnimation{
+		every: every,
+		delay: delay,
+	}
+}
+
+// Step records a frame with the frontier of the generator highlighted every few steps.
+func (a *GenerationAnimation) Step(m *Maze, s Step) {
+	a.steps++
+	if a.steps%a.every != 0 {
+		return
+	}
+
+	a.frames = append(a.frames, m.generationFrame(s.Frontier))
+}
+
+// SaveGIF writes the animation to outImage, see WriteGIF.
+func (a *GenerationAnimation) SaveGIF(outImage string, m *Maze) error {
+	f, err := os.Create(outImage)
+	if err != nil {
+		return fmt.Errorf("could not create gif: %w", err)
+	}
+
+	err = a.WriteGIF(f, m)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("could not create gif: %w", err)
+	}
+
+	return f.Close()
+}
+
+// WriteGIF writes the recorded frames to w followed by a frame of the finished maze m, which is held on
+// screen for a couple of seconds before the animation loops.
+func (a *GenerationAnimation) WriteGIF(w io.Writer, m *Maze) error {
+	cellWidth, cellHeight, wallWidth, xOffset, yOffset, margin := getMeasurements(m.Cols, m.Rows)
+	final := newAnimationFrame(m)
+	m.drawMap(final, cellWidth, cellHeight, wallWidth, xOffset, yOffset, margin)
+
+	frames := make([]*image.Paletted, 0, len(a.frames)+1)
+	frames = append(append(frames, a.frames...), final)
+	delays := make([]int, len(frames))
+	for i := range delays {
+		delays[i] = a.delay
+	}
+
+	delays[len(delays)-1] = 200
+	return gif.EncodeAll(w, &gif.GIF{
+		Image: frames,
+		Delay: delays,
+	})
+}
+
+func newAnimationFrame(m *Maze) *image.Paletted {
+	cellWidth, cellHeight, wallWidth, _, _, margin := getMeasurements(m.Cols, m.Rows)
+	xDimension := m.Cols*(cellWidth+wallWidth) + margin*2
+	yDimensions := m.Rows*(cellHeight+wallWidth) + margin*2
+
+	return image.NewPaletted(image.Rect(0, 0, xDimension, yDimensions), animationPalette)
+}
+
+// generationFrame draws the cells carved so far with the frontier on top.
+func (m *Maze) generationFrame(frontier []CellIndex) *image.Paletted {
+	cellWidth, cellHeight, wallWidth, _, _, margin := getMeasurements(m.Cols, m.Rows)
+	img := newAnimationFrame(m)
+	for y, r := range m.Cells {
+		for x, c := range r {
+			if c.Blocked() {
+				continue
+			}
+
+			xOffset := margin + x*(cellWidth+wallWidth)
+			yOffset := margin + y*(cellHeight+wallWidth)
+			paintCell(img, xOffset, yOffset, cellWidth, cellHeight, color.White)
+			removeWall(img, xOffset, yOffset, cellWidth, cellHeight, wallWidth, &c)
+		}
+	}
+
+	for _, c := range frontier {
+		paintCell(img, margin+c.Col*(cellWidth+wallWidth), margin+c.Row*(cellHeight+wallWidth), cellWidth,
+			cellHeight, frontierColour)
+	}
+
+	return img
+}
@@ -0,0 +1,253 @@
+package maze
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// JSONVersion is the version of the JSON format written by MarshalJSON.
+const JSONVersion = 1
+
+// bits used to store which sides of a cell are open
+const (
+	passageTop = 1 << iota
+	passageRight
+	passageBottom
+	passageLeft
+)
+
+type cellIndexJSON struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+type mazeJSON struct {
+	Version int           `json:"version"`
+	Rows    int           `json:"rows"`
+	Cols    int           `json:"cols"`
+	Start   cellIndexJSON `json:"start"`
+	End     cellIndexJSON `json:"end"`
+	Seed    int64         `json:"seed"`
+	// Cells has a string per row with a hex digit per cell, the digit holds
+	// which sides of the cell are open using the passage bits.
+	Cells []string `json:"cells"`
+	// Costs has the cost of every cell by row, it is left out when every cell costs 1.
+	Costs [][]int `json:"costs,omitempty"`
+}
+
+func (m *Maze) MarshalJSON() ([]byte, error) {
+	out := mazeJSON{
+		Version: JSONVersion,
+		Rows:    m.Rows,
+		Cols:    m.Cols,
+		Start: cellIndexJSON{
+			Row: m.Start.Row,
+			Col: m.Start.Col,
+		},
+		End: cellIndexJSON{
+			Row: m.End.Row,
+			Col: m.End.Col,
+		},
+		Seed:  m.Seed,
+		Cells: make([]string, m.Rows),
+	}
+
+	row := make([]byte, m.Cols)
+	for r := range m.Cells {
+		for c := range m.Cells[r] {
+			row[c] = strconv.FormatUint(uint64(m.Cells[r][c].passages()), 16)[0]
+		}
+
+		out.Cells[r] = string(row)
+	}
+
+	if m.Weighted() {
+		out.Costs = make([][]int, m.Rows)
+		for r := range m.Cells {
+			out.Costs[r] = make([]int, m.Cols)
+			for c := range m.Cells[r] {
+				out.Costs[r][c] = m.Cost(CellIndex{Row: r, Col: c})
+			}
+		}
+	}
+
+	return json.Marshal(out)
+}
+
+func (m *Maze) UnmarshalJSON(data []byte) error {
+	var in mazeJSON
+	err := json.Unmarshal(data, &in)
+	if err != nil {
+		return err
+	}
+
+	if in.Version != JSONVersion {
+		return fmt.Errorf("unsupported maze version %d", in.Version)
+	}
+
+	if in.Rows < 1 || in.Cols < 1 {
+		return fmt.Errorf("maze must have at least one row and column but has %dx%d", in.Rows, in.Cols)
+	}
+
+	if len(in.Cells) != in.Rows {
+		return fmt.Errorf("maze has %d rows but %d rows of cells", in.Rows, len(in.Cells))
+	}
+
+	passages := make([][]uint8, in.Rows)
+	for r, row := range in.Cells {
+		if len(row) != in.Cols {
+			return fmt.Errorf("row %d has %d cells but the maze has %d columns", r, len(row), in.Cols)
+		}
+
+		passages[r] = make([]uint8, in.Cols)
+		for c := range row {
+			p, err := strconv.ParseUint(row[c:c+1], 16, 8)
+			if err != nil {
+				return fmt.Errorf("cell at row %d column %d: invalid value %q", r, c, row[c])
+			}
+
+			passages[r][c] = uint8(p)
+		}
+	}
+
+	loaded, err := fromPassages(in.Rows, in.Cols, passages,
+		CellIndex{Row: in.Start.Row, Col: in.Start.Col}, CellIndex{Row: in.End.Row, Col: in.End.Col})
+	if err != nil {
+		return err
+	}
+
+	if in.Costs != nil {
+		err = loaded.setCosts(in.Costs)
+		if err != nil {
+			return err
+		}
+	}
+
+	loaded.Seed = in.Seed
+	*m = *loaded
+	return nil
+}
+
+// setCosts sets the cost of every cell from costs, which has a row of costs for every row of the maze.
+func (m *Maze) setCosts(costs [][]int) error {
+	if len(costs) != m.Rows {
+		return fmt.Errorf("maze has %d rows but %d rows of costs", m.Rows, len(costs))
+	}
+
+	for r, row := range costs {
+		if len(row) != m.Cols {
+			return fmt.Errorf("row %d has %d costs but the maze has %d columns", r, len(row), m.Cols)
+		}
+
+		for c, cost := range row {
+			if cost < 1 || cost > MaxCost {
+				return fmt.Errorf("cell at row %d column %d: cost %d is not between 1 and %d", r, c, cost, MaxCost)
+			}
+
+			m.Cells[r][c].Cost = uint8(cost)
+		}
+	}
+
+	return nil
+}
+
+// Load reads a maze written by Save.
+func Load(r io.Reader) (*Maze, error) {
+	m := &Maze{}
+	err := json.NewDecoder(r).Decode(m)
+	if err != nil {
+		return nil, fmt.Errorf("could not load maze: %w", err)
+	}
+
+	return m, nil
+}
+
+// Save writes the maze as JSON to w.
+func (m *Maze) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(m)
+}
+
+func (c *Cell) passages() uint8 {
+	var p uint8
+	if c.Top {
+		p |= passageTop
+	}
+
+	if c.Right {
+		p |= passageRight
+	}
+
+	if c.Bottom {
+		p |= passageBottom
+	}
+
+	if c.Left {
+		p |= passageLeft
+	}
+
+	return p
+}
+
+// fromPassages builds a maze from the open sides of every cell, checking neighbouring cells agree on the
+// walls between them and nothing leads outside the maze.
+func fromPassages(rows, cols int, passages [][]uint8, start, end CellIndex) (*Maze, error) {
+	m := &Maze{
+		Rows:  rows,
+		Cols:  cols,
+		Start: start,
+		End:   end,
+		Cells: make([][]Cell, rows),
+	}
+
+	if !m.inside(start) {
+		return nil, fmt.Errorf("start %+v is outside the maze", start)
+	}
+
+	if !m.inside(end) {
+		return nil, fmt.Errorf("end %+v is outside the maze", end)
+	}
+
+	for r := 0; r < rows; r++ {
+		m.Cells[r] = make([]Cell, cols)
+		for c := 0; c < cols; c++ {
+			p := passages[r][c]
+			if p > passageTop|passageRight|passageBottom|passageLeft {
+				return nil, fmt.Errorf("cell at row %d column %d: invalid value %d", r, c, p)
+			}
+
+			m.Cells[r][c] = Cell{
+				Top:    p&passageTop != 0,
+				Right:  p&passageRight != 0,
+				Bottom: p&passageBottom != 0,
+				Left:   p&passageLeft != 0,
+				In:     true,
+			}
+		}
+	}
+
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			cell := m.Cells[r][c]
+			if (r == 0 && cell.Top) || (r == rows-1 && cell.Bottom) || (c == 0 && cell.Left) ||
+				(c == cols-1 && cell.Right) {
+				return nil, fmt.Errorf("cell at row %d column %d opens outside the maze", r, c)
+			}
+
+			if c+1 < cols && cell.Right != m.Cells[r][c+1].Left {
+				return nil, fmt.Errorf("cells at row %d columns %d and %d disagree on the wall between them",
+					r, c, c+1)
+			}
+
+			if r+1 < rows && cell.Bottom != m.Cells[r+1][c].Top {
+				return nil, fmt.Errorf("cells at rows %d and %d column %d disagree on the wall between them",
+					r, r+1, c)
+			}
+		}
+	}
+
+	m.Cells[start.Row][start.Col].Start = true
+	m.Cells[end.Row][end.Col].End = true
+	return m, nil
+}
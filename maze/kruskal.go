@@ -0,0 +1,86 @@
+package maze
+
+import "math/rand"
+
+// Kruskal generates mazes using a randomized version of Kruskal's algorithm.
+// Walls are removed in random order whenever they separate two cells that are
+// not yet connected, which gives a maze with many short dead ends spread
+// evenly over the grid.
+type Kruskal struct{}
+
+func (Kruskal) Generate(m *Maze, r *rand.Rand, step StepFunc) {
+	walls := make([][2]CellIndex, 0, 2*m.Rows*m.Cols)
+	for row := 0; row < m.Rows; row++ {
+		for c := 0; c < m.Cols; c++ {
+			if c+1 < m.Cols {
+				walls = append(walls, [2]CellIndex{{Row: row, Col: c}, {Row: row, Col: c + 1}})
+			}
+
+			if row+1 < m.Rows {
+				walls = append(walls, [2]CellIndex{{Row: row, Col: c}, {Row: row + 1, Col: c}})
+			}
+		}
+	}
+
+	r.Shuffle(len(walls), func(i, j int) {
+		walls[i], walls[j] = walls[j], walls[i]
+	})
+
+	sets := newDisjointSet(m.Rows * m.Cols)
+	for _, w := range walls {
+		if sets.union(w[0].GetID(m.Cols), w[1].GetID(m.Cols)) {
+			m.link(w[0], w[1])
+			step.notify(m, w[0], w[1], nil)
+		}
+	}
+
+	// a single cell maze has no walls to remove
+	m.Cells[0][0].In = true
+}
+
+// disjointSet is a union-find structure over the integers [0, n).
+type disjointSet struct {
+	parent []int
+	rank   []int
+}
+
+func newDisjointSet(n int) *disjointSet {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+
+	return &disjointSet{
+		parent: parent,
+		rank:   make([]int, n),
+	}
+}
+
+func (d *disjointSet) find(x int) int {
+	for d.parent[x] != x {
+		d.parent[x] = d.parent[d.parent[x]]
+		x = d.parent[x]
+	}
+
+	return x
+}
+
+// union joins the sets containing a and b and reports whether they were different sets.
+func (d *disjointSet) union(a, b int) bool {
+	ra, rb := d.find(a), d.find(b)
+	if ra == rb {
+		return false
+	}
+
+	switch {
+	case d.rank[ra] < d.rank[rb]:
+		d.parent[ra] = rb
+	case d.rank[ra] > d.rank[rb]:
+		d.parent[rb] = ra
+	default:
+		d.parent[rb] = ra
+		d.rank[ra]++
+	}
+
+	return true
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"image/draw"
 	"image/png"
+	"io"
 	"math/rand"
 	"os"
 	"time"
@@ -33,6 +35,9 @@ type Cell struct {
 	Visited bool
 	End     bool
 	Start   bool
+
+	// Cost of moving into the cell, like a patch of mud would, 0 is the same as 1.
+	Cost uint8
 }
 
 func (c *Cell) Blocked() bool {
@@ -45,8 +50,13 @@ type Maze struct {
 	Start CellIndex
 	End   CellIndex
 	Cells [][]Cell
+	// Seed is the seed of the random source the maze was generated with, generating a maze
+	// with the same seed, dimensions and generator gives back the same maze.
+	Seed int64
 }
 
+// AsciiDraw prints the maze to stdout in a compact format with three characters per cell, WriteText
+// draws mazes that are easier to read.
 func (m *Maze) AsciiDraw() {
 	for i := 0; i < m.Cols; i++ {
 		fmt.Printf("___")
@@ -82,66 +92,39 @@ func (m *Maze) AsciiDraw() {
 }
 
 func (m *Maze) Create(rows, cols int) {
-	rand.Seed(time.Now().Unix())
+	m.Seed = time.Now().UnixNano()
+	m.create(rows, cols, options{
+		generator: Prim{},
+		placement: RandomBorder{},
+	}, rand.New(rand.NewSource(m.Seed)))
+}
+
+func (m *Maze) create(rows, cols int, o options, r *rand.Rand) {
+	m.Rows = rows
+	m.Cols = cols
+
 	// initialise grid
 	m.Cells = make([][]Cell, rows)
 	for r := 0; r < rows; r++ {
 		m.Cells[r] = make([]Cell, cols)
 	}
 
-	// pick a random cell on the edge
-	// 0 <= Row < height
-	row := rand.Intn(rows)
-	col := rand.Intn(cols)
-
-	m.Cells[row][col].In = true
-
-	frontierSet := make(map[CellIndex]struct{})
-	frontiers := m.getFrontierCell(row, col)
-	addToSet(frontierSet, frontiers)
-
-	var step int
-	for len(frontierSet) != 0 {
-		step++
-
-		fCell := getRandomFrontierCell(frontierSet)
-		m.join(fCell.Row, fCell.Col)
-		delete(frontierSet, fCell)
-		addToSet(frontierSet, m.getFrontierCell(fCell.Row, fCell.Col))
+	o.generator.Generate(m, r, o.step)
+	if o.braid > 0 {
+		m.Braid(o.braid, r, o.step)
 	}
 
-	// choose an arbitrary Start and an End
-	startRow := rand.Intn(rows)
-	var startCol int
-	if startRow == 0 || startRow == rows-1 {
-		startCol = rand.Intn(cols)
-	} else if rand.Intn(100) < 50 {
-		startCol = cols - 1
+	if o.passages > 0 {
+		m.AddPassages(o.passages, r, o.step)
 	}
 
-	start := CellIndex{
-		Col: startCol,
-		Row: startRow,
-	}
-
-	endRow := startRow
-	endCol := startCol
-	for startRow == endRow && endCol == startCol {
-		endRow := rand.Intn(rows)
-		if endRow == 0 || endRow == rows-1 {
-			endCol = rand.Intn(cols)
-		} else if rand.Intn(100) < 50 {
-			endCol = cols - 1
-		} else {
-			endCol = 0
-		}
-	}
-
-	end := CellIndex{
-		Col: endCol,
-		Row: endRow,
+	if o.costImage != nil {
+		m.CostsFromImage(o.costImage, o.maxCost)
+	} else if o.maxCost > 1 {
+		m.RandomCosts(o.maxCost, r)
 	}
 
+	start, end := o.placement.Place(m, r)
 	m.Start = start
 	m.End = end
 	m.Cells[start.Row][start.Col].Start = true
@@ -160,98 +143,12 @@ func (m *Maze) UnVisitAll() {
 	}
 }
 
-func (m *Maze) join(row, col int) {
-	indexes := []CellIndex{
-		{
-			Row: row + 1,
-			Col: col,
-		},
-		{
-			Row: row - 1,
-			Col: col,
-		},
-		{
-			Col: col - 1,
-			Row: row,
-		},
-		{
-			Col: col + 1,
-			Row: row,
-		},
-	}
-
-	possible := make([]int, 0)
-	for i, neighbour := range indexes {
-		// outside maze
-		if neighbour.Col < 0 || neighbour.Col > m.Cols-1 || neighbour.Row < 0 || neighbour.Row > m.Rows-1 {
-			continue
-		}
-
-		if !m.Cells[neighbour.Row][neighbour.Col].Blocked() {
-			possible = append(possible, i)
-		}
-	}
-
-	if len(possible) == 0 {
-		return
-	}
-
-	chosenOne := possible[rand.Intn(len(possible))]
-	switch chosenOne {
-	case 0:
-		m.Cells[indexes[0].Row][indexes[0].Col].Top = true
-		m.Cells[row][col].Bottom = true
-	case 1:
-		m.Cells[indexes[1].Row][indexes[1].Col].Bottom = true
-		m.Cells[row][col].Top = true
-	case 2:
-		m.Cells[indexes[2].Row][indexes[2].Col].Right = true
-		m.Cells[row][col].Left = true
-	case 3:
-		m.Cells[indexes[3].Row][indexes[3].Col].Left = true
-		m.Cells[row][col].Right = true
-	}
-
-	m.Cells[row][col].In = true
-}
-
-func (m *Maze) getFrontierCell(row, col int) []CellIndex {
-	frontiers := make([]CellIndex, 0)
-	// Top, Bottom, Left, Right
-	indexes := []CellIndex{
-		{
-			Row: row + 1,
-			Col: col,
-		},
-		{
-			Row: row - 1,
-			Col: col,
-		},
-		{
-			Col: col - 1,
-			Row: row,
-		},
-		{
-			Col: col + 1,
-			Row: row,
-		},
-	}
-
-	for _, neighbour := range indexes {
-		// outside maze
-		if neighbour.Col < 0 || neighbour.Col > m.Cols-1 || neighbour.Row < 0 || neighbour.Row > m.Rows-1 {
-			continue
-		}
-
-		if m.Cells[neighbour.Row][neighbour.Col].Blocked() {
-			frontiers = append(frontiers, neighbour)
-		}
-	}
-
-	return frontiers
+func (m *Maze) Image(outImage string) error {
+	return saveImage(outImage, m.ToImage())
 }
 
-func (m *Maze) Image(outImage string) error {
+// ToImage draws the maze the same way Image does but returns the picture instead of saving it.
+func (m *Maze) ToImage() *image.RGBA {
 	cellWidth, cellHeight, wallWidth, xOffset, yOffset, margin := getMeasurements(m.Cols, m.Rows)
 	xDimension := m.Cols*(cellWidth+wallWidth) + margin*2
 	yDimensions := m.Rows*(cellHeight+wallWidth) + margin*2
@@ -260,14 +157,15 @@ func (m *Maze) Image(outImage string) error {
 
 	m.drawMap(img, cellWidth, cellHeight, wallWidth, xOffset, yOffset, margin)
 
-	return saveImage(outImage, img)
+	return img
 }
 
-func (m *Maze) drawMap(img *image.RGBA, cellWidth, cellHeight, wallWidth, xOffset, yOffset, margin int) {
+func (m *Maze) drawMap(img draw.Image, cellWidth, cellHeight, wallWidth, xOffset, yOffset, margin int) {
+	maxCost := m.maxCost()
 	for y, r := range m.Cells {
 		for x, c := range r {
 			// draw main block
-			cellColor := color.Color(color.White)
+			cellColor := m.cellColour(CellIndex{Row: y, Col: x}, maxCost)
 			if c.Start {
 				cellColor = color.Color(color.RGBA{
 					R: 255,
@@ -324,7 +222,8 @@ func generateEmptyImage(xSize, ySize int) *image.RGBA {
 	return img
 }
 
-// getMeasurements returns (scaler, cellWidth, cellHeight, wallWidth, xOffset, yOffset, margin)
+// getMeasurements returns (cellWidth, cellHeight, wallWidth, xOffset, yOffset, margin), the scale
+// is picked from the longest side so very wide or tall mazes stay the same proportions.
 func getMeasurements(cols, rows int) (int, int, int, int, int, int) {
 	var scaler int
 	if rows > cols {
@@ -357,13 +256,18 @@ func (m *Maze) ImageWithPath(path []*CellIndex, outImage string, cellColor color
 }
 
 func (m *Maze) ImageWithMultiplePaths(paths [][]*CellIndex, outImage string, cellColors []color.Color) error {
-	cellWidth, cellHeight, wallWidth, xOffset, yOffset, margin := getMeasurements(m.Cols, m.Rows)
-	xDimension := m.Cols*(cellWidth+wallWidth) + margin*2
-	yDimensions := m.Rows*(cellHeight+wallWidth) + margin*2
+	return saveImage(outImage, m.imageWithPaths(paths, cellColors))
+}
 
-	img := generateEmptyImage(xDimension, yDimensions)
+// WritePNG writes the maze to w as a PNG with the paths painted over it, each in the colour at the same
+// position, like ImageWithMultiplePaths.
+func (m *Maze) WritePNG(w io.Writer, paths [][]*CellIndex, cellColors []color.Color) error {
+	return png.Encode(w, m.imageWithPaths(paths, cellColors))
+}
 
-	m.drawMap(img, cellWidth, cellHeight, wallWidth, xOffset, yOffset, margin)
+func (m *Maze) imageWithPaths(paths [][]*CellIndex, cellColors []color.Color) *image.RGBA {
+	cellWidth, cellHeight, wallWidth, _, _, margin := getMeasurements(m.Cols, m.Rows)
+	img := m.ToImage()
 	for i, path := range paths {
 		for _, c := range path {
 			paintCell(img, margin+c.Col*(cellWidth+wallWidth), margin+c.Row*(cellHeight+wallWidth), cellWidth,
@@ -371,10 +275,10 @@ func (m *Maze) ImageWithMultiplePaths(paths [][]*CellIndex, outImage string, cel
 		}
 	}
 
-	return saveImage(outImage, img)
+	return img
 }
 
-func paintCell(img *image.RGBA, x, y, width, height int, c color.Color) {
+func paintCell(img draw.Image, x, y, width, height int, c color.Color) {
 	for xi := 0; xi < width; xi++ {
 		for yi := 0; yi < height; yi++ {
 			img.Set(xi+x, yi+y, c)
@@ -382,7 +286,7 @@ func paintCell(img *image.RGBA, x, y, width, height int, c color.Color) {
 	}
 }
 
-func paintExits(img *image.RGBA, x, y, cx, cy, cols, rows, cellWidth, cellHeight int, c color.Color) {
+func paintExits(img draw.Image, x, y, cx, cy, cols, rows, cellWidth, cellHeight int, c color.Color) {
 	if cy-1 < 0 {
 		paintCell(img, x, y-cellHeight, cellWidth, cellHeight, c)
 	} else if cy+1 >= rows {
@@ -394,7 +298,7 @@ func paintExits(img *image.RGBA, x, y, cx, cy, cols, rows, cellWidth, cellHeight
 	}
 }
 
-func removeWall(img *image.RGBA, x, y, cellWidth, cellHeight, wallWidth int, c *Cell) {
+func removeWall(img draw.Image, x, y, cellWidth, cellHeight, wallWidth int, c *Cell) {
 	if c.Top {
 		paintCell(img, x, y-wallWidth, cellWidth, wallWidth, color.White)
 	}
@@ -408,37 +312,119 @@ func removeWall(img *image.RGBA, x, y, cellWidth, cellHeight, wallWidth int, c *
 	}
 
 	if c.Bottom {
-		paintCell(img, x, y+cellHeight, wallWidth, cellHeight, color.White)
+		paintCell(img, x, y+cellHeight, cellWidth, wallWidth, color.White)
 	}
 }
 
-func NewMaze(rows, cols int) *Maze {
-	maze := &Maze{
-		Rows: rows,
-		Cols: cols,
+type options struct {
+	generator Generator
+	placement Placement
+	braid     float64
+	passages  float64
+	maxCost   uint8
+	costImage image.Image
+	seed      int64
+	rand      *rand.Rand
+	step      StepFunc
+}
+
+// Option configures how NewMaze builds a maze.
+type Option func(*options)
+
+// WithGenerator selects the algorithm used to carve the maze, by default Prim is used.
+func WithGenerator(g Generator) Option {
+	return func(o *options) {
+		o.generator = g
 	}
+}
 
-	maze.Create(rows, cols)
-	return maze
+// WithPlacement selects how the start and end of the maze are picked, by default RandomBorder is used.
+func WithPlacement(p Placement) Option {
+	return func(o *options) {
+		o.placement = p
+	}
 }
 
-func addToSet(set map[CellIndex]struct{}, sl []CellIndex) {
-	for _, x := range sl {
-		set[x] = struct{}{}
+// WithBraid makes NewMaze remove around fraction of the dead ends, between 0 and 1, once the maze is
+// generated, see Maze.Braid.
+func WithBraid(fraction float64) Option {
+	return func(o *options) {
+		o.braid = fraction
 	}
 }
 
-func getRandomFrontierCell(set map[CellIndex]struct{}) CellIndex {
-	n := len(set)
-	chosenOne := rand.Intn(n)
-	var count int
-	for k := range set {
-		if count == chosenOne {
-			return k
-		}
+// WithExtraPassages makes NewMaze knock down around fraction of the walls left between cells, between 0 and 1,
+// once the maze is generated, see Maze.AddPassages.
+func WithExtraPassages(fraction float64) Option {
+	return func(o *options) {
+		o.passages = fraction
+	}
+}
+
+// WithRandomCosts makes NewMaze cover the maze in patches costing up to maxCost to move through, see
+// Maze.RandomCosts.
+func WithRandomCosts(maxCost uint8) Option {
+	return func(o *options) {
+		o.maxCost = maxCost
+	}
+}
+
+// WithCostImage makes NewMaze take the cost of every cell, up to maxCost, from how dark img is over it, see
+// Maze.CostsFromImage. It replaces WithRandomCosts.
+func WithCostImage(img image.Image, maxCost uint8) Option {
+	return func(o *options) {
+		o.costImage = img
+		o.maxCost = maxCost
+	}
+}
+
+// WithSeed makes NewMaze use a random source seeded with seed, by default the seed is
+// taken from the current time.
+func WithSeed(seed int64) Option {
+	return func(o *options) {
+		o.seed = seed
+		o.rand = nil
+	}
+}
+
+// WithRand makes NewMaze draw all its random numbers from r. The seed of r cannot be
+// recovered so Maze.Seed is left as 0.
+func WithRand(r *rand.Rand) Option {
+	return func(o *options) {
+		o.seed = 0
+		o.rand = r
+	}
+}
+
+// WithStepFunc makes NewMaze call f after every wall the generator removes.
+func WithStepFunc(f StepFunc) Option {
+	return func(o *options) {
+		o.step = f
+	}
+}
+
+func NewMaze(rows, cols int, opts ...Option) *Maze {
+	o := options{
+		generator: Prim{},
+		placement: RandomBorder{},
+		seed:      time.Now().UnixNano(),
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
 
-		count++
+	r := o.rand
+	if r == nil {
+		r = rand.New(rand.NewSource(o.seed))
 	}
 
-	return CellIndex{}
+	maze := &Maze{
+		Rows: rows,
+		Cols: cols,
+		Seed: o.seed,
+	}
+
+	maze.create(rows, cols, o, r)
+	return maze
 }
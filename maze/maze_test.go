@@ -0,0 +1,193 @@
+package maze
+
+import (
+	"fmt"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// rectangularSizes are the shapes that went wrong when rows and columns were mixed up.
+var rectangularSizes = [][2]int{{1, 500}, {500, 3}, {3, 500}, {500, 1}}
+
+// forEachGenerator runs f on a maze of every size and generator.
+func forEachGenerator(t *testing.T, sizes [][2]int, f func(t *testing.T, m *Maze)) {
+	for _, size := range sizes {
+		for _, name := range GeneratorNames() {
+			size, name := size, name
+			t.Run(fmt.Sprintf("%dx%d/%s", size[0], size[1], name), func(t *testing.T) {
+				g, err := GetGenerator(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				f(t, NewMaze(size[0], size[1], WithSeed(11), WithGenerator(g)))
+			})
+		}
+	}
+}
+
+// checkPerfect fails t unless m passes checkConnected and there is exactly one way between any two cells.
+func checkPerfect(t *testing.T, m *Maze) {
+	t.Helper()
+	// a connected maze with one passage fewer than cells has no loops
+	if passages := checkConnected(t, m); passages != m.Rows*m.Cols-1 {
+		t.Fatalf("maze has %d passages, a perfect maze of %d cells has %d", passages, m.Rows*m.Cols,
+			m.Rows*m.Cols-1)
+	}
+}
+
+// checkConnected fails t unless every cell of m is in the maze, the walls of neighbouring cells agree, none
+// lead outside and every cell can be reached from the start. It returns the number of passages between cells.
+func checkConnected(t *testing.T, m *Maze) int {
+	t.Helper()
+	if len(m.Cells) != m.Rows {
+		t.Fatalf("maze has %d rows of cells, want %d", len(m.Cells), m.Rows)
+	}
+
+	var passages int
+	for r := range m.Cells {
+		if len(m.Cells[r]) != m.Cols {
+			t.Fatalf("row %d has %d cells, want %d", r, len(m.Cells[r]), m.Cols)
+		}
+
+		for c, cell := range m.Cells[r] {
+			if !cell.In {
+				t.Fatalf("cell at row %d column %d is not in the maze", r, c)
+			}
+
+			if (r == 0 && cell.Top) || (r == m.Rows-1 && cell.Bottom) || (c == 0 && cell.Left) ||
+				(c == m.Cols-1 && cell.Right) {
+				t.Fatalf("cell at row %d column %d opens outside the maze", r, c)
+			}
+
+			if c+1 < m.Cols && cell.Right != m.Cells[r][c+1].Left {
+				t.Fatalf("cells at row %d columns %d and %d disagree on their wall", r, c, c+1)
+			}
+
+			if r+1 < m.Rows && cell.Bottom != m.Cells[r+1][c].Top {
+				t.Fatalf("cells at column %d rows %d and %d disagree on their wall", c, r, r+1)
+			}
+
+			if cell.Right {
+				passages++
+			}
+
+			if cell.Bottom {
+				passages++
+			}
+		}
+	}
+
+	for r, row := range m.distances([]CellIndex{m.Start}) {
+		for c, d := range row {
+			if d < 0 {
+				t.Fatalf("cell at row %d column %d cannot be reached from the start", r, c)
+			}
+		}
+	}
+
+	if !m.inside(m.Start) || !m.inside(m.End) {
+		t.Fatalf("start %+v or end %+v is outside the maze", m.Start, m.End)
+	}
+
+	if !m.Cells[m.Start.Row][m.Start.Col].Start || !m.Cells[m.End.Row][m.End.Col].End {
+		t.Fatal("start or end cell is not marked")
+	}
+
+	return passages
+}
+
+func TestGenerateRectangular(t *testing.T) {
+	forEachGenerator(t, rectangularSizes, func(t *testing.T, m *Maze) {
+		checkPerfect(t, m)
+	})
+}
+
+func TestAsciiDrawRectangular(t *testing.T) {
+	forEachGenerator(t, rectangularSizes, func(t *testing.T, m *Maze) {
+		out := captureStdout(t, m.AsciiDraw)
+		lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+		if len(lines) != m.Rows+1 {
+			t.Fatalf("drew %d lines, want %d", len(lines), m.Rows+1)
+		}
+
+		for i, line := range lines {
+			if len(line) != m.Cols*3 {
+				t.Fatalf("line %d is %d characters, want %d", i+1, len(line), m.Cols*3)
+			}
+		}
+
+		got, err := ParseASCII(strings.NewReader(out))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sameMaze(t, m, got)
+	})
+}
+
+func TestImageRectangular(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gomaze")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+	forEachGenerator(t, rectangularSizes, func(t *testing.T, m *Maze) {
+		name := filepath.Join(dir, fmt.Sprintf("%dx%d.png", m.Rows, m.Cols))
+		err := m.Image(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer f.Close()
+		img, err := png.Decode(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cellWidth, cellHeight, wallWidth, _, _, margin := getMeasurements(m.Cols, m.Rows)
+		width, height := m.Cols*(cellWidth+wallWidth)+margin*2, m.Rows*(cellHeight+wallWidth)+margin*2
+		if img.Bounds().Dx() != width || img.Bounds().Dy() != height {
+			t.Fatalf("image is %dx%d pixels, want %dx%d", img.Bounds().Dx(), img.Bounds().Dy(), width, height)
+		}
+
+		got, err := FromImage(img, ImageOptions{Pitch: imagePitch(m.Rows, m.Cols)})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sameMaze(t, m, got)
+	})
+}
+
+// captureStdout returns what f prints to stdout.
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		out, _ := ioutil.ReadAll(r)
+		done <- string(out)
+	}()
+
+	f()
+	os.Stdout = stdout
+	w.Close()
+	return <-done
+}
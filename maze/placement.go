@@ -0,0 +1,122 @@
+package maze
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// Placement picks the start and end of a maze once all its passages are carved. All random choices must be
+// drawn from r so that mazes can be reproduced from their seed.
+type Placement interface {
+	Place(m *Maze, r *rand.Rand) (start, end CellIndex)
+}
+
+// RandomBorder places the start and end on two random cells on the border of the maze. They are only the
+// same cell when the maze has a single cell.
+type RandomBorder struct{}
+
+func (RandomBorder) Place(m *Maze, r *rand.Rand) (CellIndex, CellIndex) {
+	start := randomBorderCell(r, m.Rows, m.Cols)
+	end := start
+	for end == start && m.Rows*m.Cols > 1 {
+		end = randomBorderCell(r, m.Rows, m.Cols)
+	}
+
+	return start, end
+}
+
+func randomBorderCell(r *rand.Rand, rows, cols int) CellIndex {
+	row := r.Intn(rows)
+	var col int
+	if row == 0 || row == rows-1 {
+		col = r.Intn(cols)
+	} else if r.Intn(100) < 50 {
+		col = cols - 1
+	}
+
+	return CellIndex{
+		Col: col,
+		Row: row,
+	}
+}
+
+// OppositeCorners places the start in a random corner of the maze and the end in the opposite one.
+type OppositeCorners struct{}
+
+func (OppositeCorners) Place(m *Maze, r *rand.Rand) (CellIndex, CellIndex) {
+	start := CellIndex{
+		Row: r.Intn(2) * (m.Rows - 1),
+		Col: r.Intn(2) * (m.Cols - 1),
+	}
+
+	end := CellIndex{
+		Row: m.Rows - 1 - start.Row,
+		Col: m.Cols - 1 - start.Col,
+	}
+
+	return start, end
+}
+
+// FixedPlacement places the start and end on the given cells, which must be inside the maze.
+type FixedPlacement struct {
+	Start CellIndex
+	End   CellIndex
+}
+
+func (p FixedPlacement) Place(*Maze, *rand.Rand) (CellIndex, CellIndex) {
+	return p.Start, p.End
+}
+
+// LongestPath places the start and end as far apart as the passages allow, which makes the solution as long
+// as it can be. The cell furthest from the first cell is found and then the cell furthest from that one, on a
+// perfect maze those two are the ends of its longest path. Braided mazes can have longer ones.
+type LongestPath struct{}
+
+func (LongestPath) Place(m *Maze, r *rand.Rand) (CellIndex, CellIndex) {
+	start := m.furthest(CellIndex{})
+	return start, m.furthest(start)
+}
+
+// furthest returns the cell with the most moves from c, the first one in row order on a tie.
+func (m *Maze) furthest(c CellIndex) CellIndex {
+	dist := m.distances([]CellIndex{c})
+	furthest := c
+	for row := range dist {
+		for col, d := range dist[row] {
+			if d > dist[furthest.Row][furthest.Col] {
+				furthest = CellIndex{Row: row, Col: col}
+			}
+		}
+	}
+
+	return furthest
+}
+
+var placements = map[string]Placement{
+	"border":  RandomBorder{},
+	"corners": OppositeCorners{},
+	"longest": LongestPath{},
+}
+
+// GetPlacement returns the placement registered under name, FixedPlacement is not registered as it needs
+// its cells.
+func GetPlacement(name string) (Placement, error) {
+	p, ok := placements[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown placement: %s", name)
+	}
+
+	return p, nil
+}
+
+// PlacementNames returns the names of all the available placements sorted alphabetically.
+func PlacementNames() []string {
+	names := make([]string, 0, len(placements))
+	for name := range placements {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,75 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRandomBorder(t *testing.T) {
+	sizes := [][2]int{{1, 2}, {2, 1}, {1, 1}, {2, 2}, {7, 9}}
+	for _, size := range sizes {
+		m := &Maze{Rows: size[0], Cols: size[1]}
+		for seed := int64(0); seed < 200; seed++ {
+			start, end := RandomBorder{}.Place(m, rand.New(rand.NewSource(seed)))
+			for _, c := range []CellIndex{start, end} {
+				onBorder := c.Row == 0 || c.Row == m.Rows-1 || c.Col == 0 || c.Col == m.Cols-1
+				if !m.inside(c) || !onBorder {
+					t.Fatalf("%+v is not on the border of a %dx%d maze", c, m.Rows, m.Cols)
+				}
+			}
+
+			if start == end && m.Rows*m.Cols > 1 {
+				t.Fatalf("start and end are both %+v on a %dx%d maze with seed %d", start, m.Rows, m.Cols, seed)
+			}
+		}
+	}
+}
+
+func TestOppositeCorners(t *testing.T) {
+	m := &Maze{Rows: 3, Cols: 4}
+	corners := map[CellIndex]CellIndex{
+		{Row: 0, Col: 0}: {Row: 2, Col: 3},
+		{Row: 0, Col: 3}: {Row: 2, Col: 0},
+		{Row: 2, Col: 0}: {Row: 0, Col: 3},
+		{Row: 2, Col: 3}: {Row: 0, Col: 0},
+	}
+
+	seen := make(map[CellIndex]bool)
+	for seed := int64(0); seed < 100; seed++ {
+		start, end := OppositeCorners{}.Place(m, rand.New(rand.NewSource(seed)))
+		want, ok := corners[start]
+		if !ok || end != want {
+			t.Fatalf("got start %+v and end %+v, want opposite corners", start, end)
+		}
+
+		seen[start] = true
+	}
+
+	if len(seen) != len(corners) {
+		t.Fatalf("the start was only put in %d of the corners", len(seen))
+	}
+}
+
+func TestLongestPath(t *testing.T) {
+	forEachGenerator(t, [][2]int{{1, 30}, {12, 17}, {25, 25}}, func(t *testing.T, m *Maze) {
+		start, end := LongestPath{}.Place(m, rand.New(rand.NewSource(1)))
+
+		// the diameter of the tree the maze makes is the longest of the distances between any two cells
+		var diameter int
+		for row := range m.Cells {
+			for col := range m.Cells[row] {
+				for _, r := range m.distances([]CellIndex{{Row: row, Col: col}}) {
+					for _, d := range r {
+						if d > diameter {
+							diameter = d
+						}
+					}
+				}
+			}
+		}
+
+		if got := m.distances([]CellIndex{start})[end.Row][end.Col]; got != diameter {
+			t.Fatalf("start %+v and end %+v are %d moves apart, the longest path has %d", start, end, got, diameter)
+		}
+	})
+}
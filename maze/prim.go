@@ -0,0 +1,102 @@
+package maze
+
+import "math/rand"
+
+// Prim generates mazes using a randomized version of Prim's algorithm. The
+// maze grows from a single cell by joining a random frontier cell each step,
+// which gives lots of short dead ends.
+type Prim struct{}
+
+func (Prim) Generate(m *Maze, r *rand.Rand, step StepFunc) {
+	row := r.Intn(m.Rows)
+	col := r.Intn(m.Cols)
+
+	m.Cells[row][col].In = true
+
+	frontierSet := newFrontierSet()
+	frontiers := m.getFrontierCell(row, col)
+	frontierSet.add(frontiers)
+
+	for frontierSet.len() != 0 {
+		fCell := frontierSet.removeRandom(r)
+		joined, ok := m.join(fCell.Row, fCell.Col, r)
+		frontierSet.add(m.getFrontierCell(fCell.Row, fCell.Col))
+		if ok {
+			step.notify(m, joined, fCell, func() []CellIndex {
+				return frontierSet.cells
+			})
+		}
+	}
+}
+
+// join links the cell to a random neighbour that is already part of the maze and returns that neighbour.
+func (m *Maze) join(row, col int, r *rand.Rand) (CellIndex, bool) {
+	possible := make([]CellIndex, 0)
+	for _, neighbour := range m.neighbours(row, col) {
+		if !m.Cells[neighbour.Row][neighbour.Col].Blocked() {
+			possible = append(possible, neighbour)
+		}
+	}
+
+	if len(possible) == 0 {
+		return CellIndex{}, false
+	}
+
+	chosenOne := possible[r.Intn(len(possible))]
+	m.link(CellIndex{Row: row, Col: col}, chosenOne)
+	return chosenOne, true
+}
+
+func (m *Maze) getFrontierCell(row, col int) []CellIndex {
+	frontiers := make([]CellIndex, 0)
+	for _, neighbour := range m.neighbours(row, col) {
+		if m.Cells[neighbour.Row][neighbour.Col].Blocked() {
+			frontiers = append(frontiers, neighbour)
+		}
+	}
+
+	return frontiers
+}
+
+// frontierSet is a set of cells that can remove a random element in constant time.
+// Unlike ranging over a map the element removed only depends on the random source
+// which keeps generation reproducible.
+type frontierSet struct {
+	cells    []CellIndex
+	position map[CellIndex]int
+}
+
+func newFrontierSet() *frontierSet {
+	return &frontierSet{
+		cells:    make([]CellIndex, 0),
+		position: make(map[CellIndex]int),
+	}
+}
+
+func (s *frontierSet) len() int {
+	return len(s.cells)
+}
+
+func (s *frontierSet) add(sl []CellIndex) {
+	for _, x := range sl {
+		if _, ok := s.position[x]; ok {
+			continue
+		}
+
+		s.position[x] = len(s.cells)
+		s.cells = append(s.cells, x)
+	}
+}
+
+func (s *frontierSet) removeRandom(r *rand.Rand) CellIndex {
+	i := r.Intn(len(s.cells))
+	chosenOne := s.cells[i]
+
+	last := s.cells[len(s.cells)-1]
+	s.cells[i] = last
+	s.position[last] = i
+	s.cells = s.cells[:len(s.cells)-1]
+	delete(s.position, chosenOne)
+
+	return chosenOne
+}
@@ -0,0 +1,215 @@
+package maze
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+	"math"
+	"os"
+)
+
+var (
+	visitedColour = color.RGBA{
+		R: 255,
+		G: 220,
+		B: 150,
+		A: 255,
+	}
+
+	searchPathColour = color.RGBA{
+		R: 100,
+		B: 100,
+		A: 255,
+	}
+
+	searchPalette = append(append(color.Palette{}, animationPalette...), visitedColour, searchPathColour)
+)
+
+// SearchEventKind is what happened to a cell during a search.
+type SearchEventKind int
+
+const (
+	// SearchEnqueue means the cell was added to the frontier of the search.
+	SearchEnqueue SearchEventKind = iota
+	// SearchExpand means the cell was taken off the frontier and visited.
+	SearchExpand
+)
+
+type SearchEvent struct {
+	Kind SearchEventKind
+	Cell CellIndex
+}
+
+// SearchRecording is everything a path finding algorithm did while solving a maze, in order.
+type SearchRecording struct {
+	Events []SearchEvent
+	Path   []*CellIndex
+}
+
+// SaveSearchGIF writes the animation of the recordings to outImage, see WriteSearchGIF.
+func (m *Maze) SaveSearchGIF(outImage string, recordings []SearchRecording, every, delay int) error {
+	f, err := os.Create(outImage)
+	if err != nil {
+		return fmt.Errorf("could not create gif: %w", err)
+	}
+
+	err = m.WriteSearchGIF(f, recordings, every, delay)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("could not create gif: %w", err)
+	}
+
+	return f.Close()
+}
+
+// WriteSearchGIF writes an animated GIF to w replaying each recording on a copy of the maze, tiled next to
+// each other so the order the searches explore the maze in can be compared. A frame is taken every given
+// number of expanded cells and shown for delay hundredths of a second, visited cells and the frontier are
+// drawn in different colours and the last frame shows the paths found.
+func (m *Maze) WriteSearchGIF(w io.Writer, recordings []SearchRecording, every, delay int) error {
+	if len(recordings) == 0 {
+		return fmt.Errorf("no searches to animate")
+	}
+
+	if every < 1 {
+		every = 1
+	}
+
+	cellWidth, cellHeight, wallWidth, _, _, margin := getMeasurements(m.Cols, m.Rows)
+	tileWidth := m.Cols*(cellWidth+wallWidth) + margin*2
+	tileHeight := m.Rows*(cellHeight+wallWidth) + margin*2
+	tilesAcross := int(math.Ceil(math.Sqrt(float64(len(recordings)))))
+	tilesDown := (len(recordings) + tilesAcross - 1) / tilesAcross
+	bounds := image.Rect(0, 0, tilesAcross*tileWidth, tilesDown*tileHeight)
+
+	replays := make([]*searchReplay, len(recordings))
+	for i := range recordings {
+		replays[i] = newSearchReplay(m, &recordings[i])
+	}
+
+	anim := &gif.GIF{}
+	for done := false; !done; {
+		done = true
+		for _, replay := range replays {
+			if replay.advance(every) {
+				done = false
+			}
+		}
+
+		// the last frame has every search finished so it also shows the paths
+		img := image.NewPaletted(bounds, searchPalette)
+		for i, replay := range replays {
+			tile := translatedImage{
+				Image: img,
+				dx:    (i % tilesAcross) * tileWidth,
+				dy:    (i / tilesAcross) * tileHeight,
+			}
+
+			m.drawMap(tile, cellWidth, cellHeight, wallWidth, margin, margin, margin)
+			replay.draw(m, tile, cellWidth, cellHeight, wallWidth, margin, done)
+		}
+
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, delay)
+	}
+
+	anim.Delay[len(anim.Delay)-1] = 300
+	return gif.EncodeAll(w, anim)
+}
+
+const (
+	replayUnseen uint8 = iota
+	replayFrontier
+	replayVisited
+)
+
+// searchReplay steps through a recording keeping the state of every cell.
+type searchReplay struct {
+	recording *SearchRecording
+	next      int
+	state     [][]uint8
+}
+
+func newSearchReplay(m *Maze, recording *SearchRecording) *searchReplay {
+	state := make([][]uint8, m.Rows)
+	for r := range state {
+		state[r] = make([]uint8, m.Cols)
+	}
+
+	return &searchReplay{
+		recording: recording,
+		state:     state,
+	}
+}
+
+// advance applies events until n more cells have been expanded and reports whether there are events left.
+func (s *searchReplay) advance(n int) bool {
+	var expanded int
+	for s.next < len(s.recording.Events) && expanded < n {
+		e := s.recording.Events[s.next]
+		switch e.Kind {
+		case SearchEnqueue:
+			s.state[e.Cell.Row][e.Cell.Col] = replayFrontier
+		case SearchExpand:
+			s.state[e.Cell.Row][e.Cell.Col] = replayVisited
+			expanded++
+		}
+
+		s.next++
+	}
+
+	return s.next < len(s.recording.Events)
+}
+
+func (s *searchReplay) draw(m *Maze, img draw.Image, cellWidth, cellHeight, wallWidth, margin int,
+	withPath bool) {
+	for r, row := range s.state {
+		for c, state := range row {
+			// keep the start and end visible
+			if m.Cells[r][c].Start || m.Cells[r][c].End {
+				continue
+			}
+
+			var cellColor color.Color
+			switch state {
+			case replayFrontier:
+				cellColor = frontierColour
+			case replayVisited:
+				cellColor = visitedColour
+			default:
+				continue
+			}
+
+			paintCell(img, margin+c*(cellWidth+wallWidth), margin+r*(cellHeight+wallWidth), cellWidth, cellHeight,
+				cellColor)
+		}
+	}
+
+	if !withPath {
+		return
+	}
+
+	for _, c := range s.recording.Path {
+		if m.Cells[c.Row][c.Col].Start || m.Cells[c.Row][c.Col].End {
+			continue
+		}
+
+		paintCell(img, margin+c.Col*(cellWidth+wallWidth), margin+c.Row*(cellHeight+wallWidth), cellWidth,
+			cellHeight, searchPathColour)
+	}
+}
+
+// translatedImage moves everything drawn on it by dx and dy, it lets the same drawing code paint
+// several tiles of one image.
+type translatedImage struct {
+	draw.Image
+	dx int
+	dy int
+}
+
+func (t translatedImage) Set(x, y int, c color.Color) {
+	t.Image.Set(x+t.dx, y+t.dy, c)
+}
@@ -0,0 +1,225 @@
+package maze
+
+import "errors"
+
+// ErrNoSolution is returned when the end of a maze cannot be reached from its start.
+var ErrNoSolution = errors.New("the end cannot be reached from the start")
+
+// Stats are measures of the shape of a maze that can be used to compare how hard mazes are.
+type Stats struct {
+	Cells int `json:"cells"`
+	// DeadEnds are cells with a single opening, DeadEndPercent is how many of the cells they are.
+	DeadEnds       int     `json:"deadEnds"`
+	DeadEndPercent float64 `json:"deadEndPercent"`
+	// Junctions are cells with three or four openings.
+	Junctions int `json:"junctions"`
+	// Corridors are cells with exactly two openings, Turns are the corridors where those are not opposite.
+	Corridors int `json:"corridors"`
+	Turns     int `json:"turns"`
+	// LongestCorridor is the most cells in a run of corridors joined to each other, mazes where it is long
+	// are said to have a high river factor and are easier to follow.
+	LongestCorridor int `json:"longestCorridor"`
+	// SolutionLength is the number of cells on the shortest path from the start to the end, both included.
+	SolutionLength int `json:"solutionLength"`
+	// DecisionPoints are the cells on the solution where there is more than one way forward.
+	DecisionPoints int `json:"decisionPoints"`
+	// MeanDeadEndDepth is how many cells away from the solution the dead ends are on average.
+	MeanDeadEndDepth float64 `json:"meanDeadEndDepth"`
+	// Difficulty estimates the moves someone solving the maze makes when at every decision point they wander
+	// once to the depth of an average dead end and back before taking the right way. Higher is harder.
+	Difficulty float64 `json:"difficulty"`
+}
+
+// Stats measures the maze, it returns ErrNoSolution when the end cannot be reached from the start.
+func (m *Maze) Stats() (Stats, error) {
+	s := Stats{
+		Cells: m.Rows * m.Cols,
+	}
+
+	path := m.shortestPath(m.Start, m.End)
+	if path == nil {
+		return Stats{}, ErrNoSolution
+	}
+
+	var deadEnds []CellIndex
+	for r := range m.Cells {
+		for c := range m.Cells[r] {
+			cell := &m.Cells[r][c]
+			switch cell.openings() {
+			case 1:
+				deadEnds = append(deadEnds, CellIndex{Row: r, Col: c})
+			case 2:
+				s.Corridors++
+				if cell.Top != cell.Bottom {
+					s.Turns++
+				}
+			case 3, 4:
+				s.Junctions++
+			}
+		}
+	}
+
+	s.DeadEnds = len(deadEnds)
+	s.DeadEndPercent = 100 * float64(s.DeadEnds) / float64(s.Cells)
+	s.LongestCorridor = m.longestCorridor()
+	s.SolutionLength = len(path)
+
+	for i, c := range path[:len(path)-1] {
+		ways := m.Cells[c.Row][c.Col].openings()
+		if i > 0 {
+			// the way back to the previous cell is not a way forward
+			ways--
+		}
+
+		if ways > 1 {
+			s.DecisionPoints++
+		}
+	}
+
+	if len(deadEnds) > 0 {
+		fromSolution := m.distances(path)
+		var depth int
+		for _, c := range deadEnds {
+			depth += fromSolution[c.Row][c.Col]
+		}
+
+		s.MeanDeadEndDepth = float64(depth) / float64(len(deadEnds))
+	}
+
+	s.Difficulty = float64(s.SolutionLength-1) + 2*float64(s.DecisionPoints)*s.MeanDeadEndDepth
+	return s, nil
+}
+
+// openings returns the number of open sides of the cell.
+func (c *Cell) openings() int {
+	var n int
+	for _, open := range []bool{c.Top, c.Bottom, c.Left, c.Right} {
+		if open {
+			n++
+		}
+	}
+
+	return n
+}
+
+// openNeighbours returns the cells that can be walked to from c.
+func (m *Maze) openNeighbours(c CellIndex) []CellIndex {
+	cell := &m.Cells[c.Row][c.Col]
+	neighbours := make([]CellIndex, 0, 4)
+	if cell.Top {
+		neighbours = append(neighbours, CellIndex{Row: c.Row - 1, Col: c.Col})
+	}
+
+	if cell.Bottom {
+		neighbours = append(neighbours, CellIndex{Row: c.Row + 1, Col: c.Col})
+	}
+
+	if cell.Left {
+		neighbours = append(neighbours, CellIndex{Row: c.Row, Col: c.Col - 1})
+	}
+
+	if cell.Right {
+		neighbours = append(neighbours, CellIndex{Row: c.Row, Col: c.Col + 1})
+	}
+
+	return neighbours
+}
+
+// distances returns the number of moves from the closest of from to every cell, -1 for cells that cannot be
+// reached.
+func (m *Maze) distances(from []CellIndex) [][]int {
+	dist := make([][]int, m.Rows)
+	for r := range dist {
+		dist[r] = make([]int, m.Cols)
+		for c := range dist[r] {
+			dist[r][c] = -1
+		}
+	}
+
+	queue := make([]CellIndex, 0, len(from))
+	for _, c := range from {
+		if dist[c.Row][c.Col] == -1 {
+			dist[c.Row][c.Col] = 0
+			queue = append(queue, c)
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, n := range m.openNeighbours(current) {
+			if dist[n.Row][n.Col] == -1 {
+				dist[n.Row][n.Col] = dist[current.Row][current.Col] + 1
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	return dist
+}
+
+// shortestPath returns the cells from start to end, both included, or nil when end cannot be reached.
+func (m *Maze) shortestPath(start, end CellIndex) []CellIndex {
+	toEnd := m.distances([]CellIndex{end})
+	if toEnd[start.Row][start.Col] == -1 {
+		return nil
+	}
+
+	path := []CellIndex{start}
+	for current := start; current != end; {
+		for _, n := range m.openNeighbours(current) {
+			if toEnd[n.Row][n.Col] == toEnd[current.Row][current.Col]-1 {
+				current = n
+				break
+			}
+		}
+
+		path = append(path, current)
+	}
+
+	return path
+}
+
+// longestCorridor returns the most cells in a group of corridor cells joined to each other.
+func (m *Maze) longestCorridor() int {
+	seen := make([][]bool, m.Rows)
+	for r := range seen {
+		seen[r] = make([]bool, m.Cols)
+	}
+
+	isCorridor := func(c CellIndex) bool {
+		return m.Cells[c.Row][c.Col].openings() == 2
+	}
+
+	var longest int
+	for r := range m.Cells {
+		for c := range m.Cells[r] {
+			first := CellIndex{Row: r, Col: c}
+			if seen[r][c] || !isCorridor(first) {
+				continue
+			}
+
+			// every corridor cell has two open neighbours so the groups are runs or loops
+			var length int
+			stack := []CellIndex{first}
+			seen[r][c] = true
+			for len(stack) > 0 {
+				current := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				length++
+				for _, n := range m.openNeighbours(current) {
+					if !seen[n.Row][n.Col] && isCorridor(n) {
+						seen[n.Row][n.Col] = true
+						stack = append(stack, n)
+					}
+				}
+			}
+
+			if length > longest {
+				longest = length
+			}
+		}
+	}
+
+	return longest
+}
@@ -0,0 +1,120 @@
+package maze
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  Stats
+	}{
+		{"corridor", []string{
+			"+--+--+--+--+",
+			"|S         E|",
+			"+--+--+--+--+",
+		}, Stats{
+			Cells:           4,
+			DeadEnds:        2,
+			DeadEndPercent:  50,
+			Corridors:       2,
+			LongestCorridor: 2,
+			SolutionLength:  4,
+			Difficulty:      3,
+		}},
+		{"branching", []string{
+			"+--+--+--+",
+			"|S    |  |",
+			"+--+  +  +",
+			"|       E|",
+			"+--+--+--+",
+		}, Stats{
+			// the dead ends in the top right and bottom left are a cell off the solution, the start is on it
+			Cells:            6,
+			DeadEnds:         3,
+			DeadEndPercent:   50,
+			Junctions:        1,
+			Corridors:        2,
+			Turns:            2,
+			LongestCorridor:  1,
+			SolutionLength:   4,
+			DecisionPoints:   1,
+			MeanDeadEndDepth: 2.0 / 3,
+			Difficulty:       3 + 2*2.0/3,
+		}},
+		{"loop", []string{
+			"+--+--+--+",
+			"|S       |",
+			"+  +--+  +",
+			"|        |",
+			"+--+--+  +",
+			"|E       |",
+			"+--+--+--+",
+		}, Stats{
+			// both ways round the loop are as short, the start and the junction are the decision points
+			Cells:           9,
+			DeadEnds:        1,
+			DeadEndPercent:  100.0 / 9,
+			Junctions:       1,
+			Corridors:       7,
+			Turns:           4,
+			LongestCorridor: 5,
+			SolutionLength:  7,
+			DecisionPoints:  2,
+			Difficulty:      6,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseASCII(strings.NewReader(strings.Join(tt.lines, "\n")))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := m.Stats()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the floats are compared with some room for rounding, the rest exactly
+			floats := [][2]float64{
+				{got.DeadEndPercent, tt.want.DeadEndPercent},
+				{got.MeanDeadEndDepth, tt.want.MeanDeadEndDepth},
+				{got.Difficulty, tt.want.Difficulty},
+			}
+			for _, f := range floats {
+				if math.Abs(f[0]-f[1]) > 1e-9 {
+					t.Fatalf("got %+v, want %+v", got, tt.want)
+				}
+			}
+
+			got.DeadEndPercent, got.MeanDeadEndDepth, got.Difficulty = 0, 0, 0
+			tt.want.DeadEndPercent, tt.want.MeanDeadEndDepth, tt.want.Difficulty = 0, 0, 0
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsNoSolution(t *testing.T) {
+	m, err := ParseASCII(strings.NewReader(strings.Join([]string{
+		"+--+--+--+",
+		"|S    |E |",
+		"+--+--+  +",
+		"|        |",
+		"+--+--+--+",
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = m.Stats()
+	if err != ErrNoSolution {
+		t.Fatalf("got error %v, want %v", err, ErrNoSolution)
+	}
+}
@@ -0,0 +1,173 @@
+package maze
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+)
+
+// sizes of the SVG drawing in user units, the image scales to whatever size it is shown at
+const (
+	svgCellSize  = 10
+	svgWallWidth = 2
+	svgMargin    = 10
+)
+
+// PathStyle is how a path is drawn on an SVG.
+type PathStyle struct {
+	Colour color.Color
+	// Width of the line as a fraction of the size of a cell, 0 means 0.4.
+	Width float64
+}
+
+// SVG writes the maze as an SVG to outImage.
+func (m *Maze) SVG(outImage string) error {
+	return m.SVGWithMultiplePaths(nil, outImage, nil)
+}
+
+// SVGWithPath writes the maze to outImage as an SVG with path drawn over it.
+func (m *Maze) SVGWithPath(path []*CellIndex, outImage string, style PathStyle) error {
+	return m.SVGWithMultiplePaths([][]*CellIndex{path}, outImage, []PathStyle{style})
+}
+
+// SVGWithMultiplePaths writes the maze to outImage as an SVG with every path drawn over it using the style at
+// the same position.
+func (m *Maze) SVGWithMultiplePaths(paths [][]*CellIndex, outImage string, styles []PathStyle) error {
+	f, err := os.Create(outImage)
+	if err != nil {
+		return fmt.Errorf("could not create svg: %w", err)
+	}
+
+	err = m.WriteSVG(f, paths, styles)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("could not create svg: %w", err)
+	}
+
+	return f.Close()
+}
+
+// WriteSVG writes the maze as an SVG to w. Walls are drawn as line segments and each path as a polyline
+// through the centre of its cells using the style at the same position.
+func (m *Maze) WriteSVG(w io.Writer, paths [][]*CellIndex, styles []PathStyle) error {
+	if len(styles) < len(paths) {
+		return fmt.Errorf("got %d paths but only %d styles", len(paths), len(styles))
+	}
+
+	bw := bufio.NewWriter(w)
+	width := m.Cols*svgCellSize + 2*svgMargin
+	height := m.Rows*svgCellSize + 2*svgMargin
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+
+	if maxCost := m.maxCost(); maxCost > 1 {
+		for r := range m.Cells {
+			for c := range m.Cells[r] {
+				cell := CellIndex{Row: r, Col: c}
+				if m.Cost(cell) > 1 {
+					m.writeSVGMarker(bw, cell, m.cellColour(cell, maxCost))
+				}
+			}
+		}
+	}
+
+	m.writeSVGMarker(bw, m.Start, color.RGBA{R: 255, A: 255})
+	m.writeSVGMarker(bw, m.End, color.RGBA{G: 255, A: 255})
+
+	fmt.Fprintf(bw, `<path fill="none" stroke="black" stroke-width="%d" stroke-linecap="square" d="`, svgWallWidth)
+	// every wall is shared by two cells so walls are drawn per grid line, joining
+	// neighbouring walls into a single segment to keep the file small
+	for r := 0; r <= m.Rows; r++ {
+		writeSVGWallRuns(m.Cols, func(c int) bool {
+			if r == m.Rows {
+				return !m.Cells[r-1][c].Bottom
+			}
+
+			return !m.Cells[r][c].Top
+		}, func(start, length int) {
+			fmt.Fprintf(bw, "M%d %dh%d", svgMargin+start*svgCellSize, svgMargin+r*svgCellSize, length*svgCellSize)
+		})
+	}
+
+	for c := 0; c <= m.Cols; c++ {
+		writeSVGWallRuns(m.Rows, func(r int) bool {
+			if c == m.Cols {
+				return !m.Cells[r][c-1].Right
+			}
+
+			return !m.Cells[r][c].Left
+		}, func(start, length int) {
+			fmt.Fprintf(bw, "M%d %dv%d", svgMargin+c*svgCellSize, svgMargin+start*svgCellSize, length*svgCellSize)
+		})
+	}
+	fmt.Fprintln(bw, `"/>`)
+
+	for i, path := range paths {
+		writeSVGPath(bw, path, styles[i])
+	}
+
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// writeSVGWallRuns calls draw with the start and length of every run of consecutive walls along a grid line
+// of n cells.
+func writeSVGWallRuns(n int, wall func(i int) bool, draw func(start, length int)) {
+	start := -1
+	for i := 0; i <= n; i++ {
+		if i < n && wall(i) {
+			if start == -1 {
+				start = i
+			}
+
+			continue
+		}
+
+		if start != -1 {
+			draw(start, i-start)
+			start = -1
+		}
+	}
+}
+
+func (m *Maze) writeSVGMarker(w io.Writer, c CellIndex, colour color.Color) {
+	fill, opacity := svgColour(colour)
+	fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" fill-opacity="%.2f"/>`+"\n",
+		svgMargin+c.Col*svgCellSize, svgMargin+c.Row*svgCellSize, svgCellSize, svgCellSize, fill, opacity)
+}
+
+func writeSVGPath(w io.Writer, path []*CellIndex, style PathStyle) {
+	if len(path) == 0 {
+		return
+	}
+
+	width := style.Width
+	if width == 0 {
+		width = 0.4
+	}
+
+	stroke, opacity := svgColour(style.Colour)
+	fmt.Fprintf(w, `<polyline fill="none" stroke="%s" stroke-opacity="%.2f" stroke-width="%.2f" `+
+		`stroke-linecap="round" stroke-linejoin="round" points="`, stroke, opacity, width*svgCellSize)
+	for i, c := range path {
+		if i > 0 {
+			fmt.Fprint(w, " ")
+		}
+
+		fmt.Fprintf(w, "%d,%d", svgMargin+c.Col*svgCellSize+svgCellSize/2, svgMargin+c.Row*svgCellSize+svgCellSize/2)
+	}
+	fmt.Fprintln(w, `"/>`)
+}
+
+// svgColour returns the colour as an SVG rgb() value and its opacity between 0 and 1.
+func svgColour(c color.Color) (string, float64) {
+	if c == nil {
+		c = color.Black
+	}
+
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("rgb(%d,%d,%d)", n.R, n.G, n.B), float64(n.A) / 255
+}
@@ -0,0 +1,294 @@
+package maze
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TextStyle is how WriteText draws the walls of a maze.
+type TextStyle int
+
+const (
+	// TextASCII draws corners with +, walls with - and |, which ParseASCII can read back.
+	TextASCII TextStyle = iota
+	// TextUnicode draws walls with box-drawing characters joined at every corner.
+	TextUnicode
+	// TextBlocks draws cells, walls and corners as squares two characters wide, full blocks for walls,
+	// so the maze keeps its proportions in a terminal.
+	TextBlocks
+)
+
+// textStyleNames are the names ParseTextStyle knows.
+var textStyleNames = map[string]TextStyle{
+	"ascii":   TextASCII,
+	"unicode": TextUnicode,
+	"blocks":  TextBlocks,
+}
+
+// ParseTextStyle returns the style called name, one of ascii, unicode or blocks.
+func ParseTextStyle(name string) (TextStyle, error) {
+	style, ok := textStyleNames[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown text style %q, available are ascii, unicode and blocks", name)
+	}
+
+	return style, nil
+}
+
+// the marks drawn on the cells of each path, the ASCII ones are used for TextASCII
+var (
+	asciiPathMarks   = []rune{'*', 'o', '#', 'x', '@'}
+	unicodePathMarks = []rune{'•', '○', '◆', '◇', '■'}
+)
+
+// TextOptions configures WriteText.
+type TextOptions struct {
+	Style TextStyle
+	// CellWidth is how many characters wide the inside of a cell is, by default 3 so marks sit in the
+	// middle. TextBlocks always uses 2.
+	CellWidth int
+	// Paths are drawn over the maze in order, each with its own mark, and the passages between
+	// consecutive cells of a path are marked too.
+	Paths [][]*CellIndex
+}
+
+// WriteText writes the maze to w as text with a line for every row of cells and every row of walls.
+// The start and end cells are marked with S and E, or SE when they are the same cell.
+func (m *Maze) WriteText(w io.Writer, opts TextOptions) error {
+	width := opts.CellWidth
+	if width < 1 {
+		width = 3
+	}
+
+	if opts.Style == TextBlocks {
+		width = 2
+	}
+
+	// a maze that starts where it ends marks the cell SE, which needs the room
+	if m.Start == m.End && width < 2 {
+		width = 2
+	}
+
+	marks := asciiPathMarks
+	if opts.Style != TextASCII {
+		marks = unicodePathMarks
+	}
+
+	t := textMaze{
+		maze:  m,
+		width: width,
+		cells: make([][]rune, m.Rows),
+		// the passages on the right and under every cell
+		right: make([][]rune, m.Rows),
+		below: make([][]rune, m.Rows),
+	}
+
+	for r := 0; r < m.Rows; r++ {
+		t.cells[r] = make([]rune, m.Cols)
+		t.right[r] = make([]rune, m.Cols)
+		t.below[r] = make([]rune, m.Cols)
+	}
+
+	for i, path := range opts.Paths {
+		t.markPath(path, marks[i%len(marks)])
+	}
+
+	t.cells[m.Start.Row][m.Start.Col] = 'S'
+	t.cells[m.End.Row][m.End.Col] = 'E'
+
+	bw := bufio.NewWriter(w)
+	for r := 0; r <= m.Rows; r++ {
+		var line string
+		switch opts.Style {
+		case TextUnicode:
+			line = t.unicodeWalls(r)
+		case TextBlocks:
+			line = t.blockWalls(r)
+		default:
+			line = t.asciiWalls(r)
+		}
+
+		_, err := bw.WriteString(line + "\n")
+		if err != nil {
+			return err
+		}
+
+		if r == m.Rows {
+			break
+		}
+
+		wall := "|"
+		if opts.Style == TextUnicode {
+			wall = "│"
+		} else if opts.Style == TextBlocks {
+			wall = "██"
+		}
+
+		_, err = bw.WriteString(t.cellLine(r, wall) + "\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// textMaze holds what is drawn inside the cells and passages of a maze, 0 where nothing is.
+type textMaze struct {
+	maze  *Maze
+	width int
+	cells [][]rune
+	right [][]rune
+	below [][]rune
+}
+
+func (t *textMaze) markPath(path []*CellIndex, mark rune) {
+	for i, c := range path {
+		t.cells[c.Row][c.Col] = mark
+		if i == 0 {
+			continue
+		}
+
+		prev := path[i-1]
+		switch {
+		case prev.Row == c.Row && prev.Col+1 == c.Col:
+			t.right[prev.Row][prev.Col] = mark
+		case prev.Row == c.Row && prev.Col == c.Col+1:
+			t.right[c.Row][c.Col] = mark
+		case prev.Col == c.Col && prev.Row+1 == c.Row:
+			t.below[prev.Row][prev.Col] = mark
+		case prev.Col == c.Col && prev.Row == c.Row+1:
+			t.below[c.Row][c.Col] = mark
+		}
+	}
+}
+
+// fill returns the inside of a cell or passage n characters wide with the mark in the middle.
+func fill(mark rune, n int) string {
+	if mark == 0 {
+		return strings.Repeat(" ", n)
+	}
+
+	return centre(string(mark), n)
+}
+
+// centre pads s with spaces on both sides to n characters.
+func centre(s string, n int) string {
+	pad := n - len([]rune(s))
+	return strings.Repeat(" ", pad/2) + s + strings.Repeat(" ", pad-pad/2)
+}
+
+// wallAbove reports whether there is a wall above cell row r, where r == Rows is under the last row.
+func (t *textMaze) wallAbove(r, c int) bool {
+	if r == 0 || r == t.maze.Rows {
+		return true
+	}
+
+	return !t.maze.Cells[r][c].Top
+}
+
+// wallLeft reports whether there is a wall left of cell column c, where c == Cols is after the last column.
+func (t *textMaze) wallLeft(r, c int) bool {
+	if c == 0 || c == t.maze.Cols {
+		return true
+	}
+
+	return !t.maze.Cells[r][c].Left
+}
+
+// cellLine draws row r of cells with the walls between them.
+func (t *textMaze) cellLine(r int, wall string) string {
+	var b strings.Builder
+	for c := 0; c < t.maze.Cols; c++ {
+		if t.wallLeft(r, c) {
+			b.WriteString(wall)
+		} else {
+			b.WriteString(fill(t.right[r][c-1], len([]rune(wall))))
+		}
+
+		if t.maze.Start == t.maze.End && t.maze.Start == (CellIndex{Row: r, Col: c}) {
+			b.WriteString(centre("SE", t.width))
+			continue
+		}
+
+		b.WriteString(fill(t.cells[r][c], t.width))
+	}
+
+	b.WriteString(wall)
+	return b.String()
+}
+
+// passage returns what to draw in the gap above cell r, c when there is no wall.
+func (t *textMaze) passage(r, c, width int) string {
+	return fill(t.below[r-1][c], width)
+}
+
+func (t *textMaze) asciiWalls(r int) string {
+	var b strings.Builder
+	for c := 0; c < t.maze.Cols; c++ {
+		b.WriteByte('+')
+		if t.wallAbove(r, c) {
+			b.WriteString(strings.Repeat("-", t.width))
+		} else {
+			b.WriteString(t.passage(r, c, t.width))
+		}
+	}
+
+	b.WriteByte('+')
+	return b.String()
+}
+
+func (t *textMaze) blockWalls(r int) string {
+	var b strings.Builder
+	for c := 0; c < t.maze.Cols; c++ {
+		b.WriteString("██")
+		if t.wallAbove(r, c) {
+			b.WriteString("██")
+		} else {
+			b.WriteString(t.passage(r, c, 2))
+		}
+	}
+
+	b.WriteString("██")
+	return b.String()
+}
+
+// boxJunctions is indexed by which arms of a corner have walls, up 1, down 2, left 4 and right 8.
+var boxJunctions = []rune(" ╵╷│╴┘┐┤╶└┌├─┴┬┼")
+
+func (t *textMaze) unicodeWalls(r int) string {
+	var b strings.Builder
+	for c := 0; c <= t.maze.Cols; c++ {
+		var arms int
+		if r > 0 && t.wallLeft(r-1, c) {
+			arms |= 1
+		}
+
+		if r < t.maze.Rows && t.wallLeft(r, c) {
+			arms |= 2
+		}
+
+		if c > 0 && t.wallAbove(r, c-1) {
+			arms |= 4
+		}
+
+		if c < t.maze.Cols && t.wallAbove(r, c) {
+			arms |= 8
+		}
+
+		b.WriteRune(boxJunctions[arms])
+		if c == t.maze.Cols {
+			break
+		}
+
+		if t.wallAbove(r, c) {
+			b.WriteString(strings.Repeat("─", t.width))
+		} else {
+			b.WriteString(t.passage(r, c, t.width))
+		}
+	}
+
+	return b.String()
+}
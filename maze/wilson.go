@@ -0,0 +1,67 @@
+package maze
+
+import "math/rand"
+
+// Wilson generates mazes using Wilson's algorithm. Loop-erased random walks
+// are started from every cell not yet in the maze until they hit the maze,
+// which picks uniformly among all the possible spanning trees.
+type Wilson struct{}
+
+func (Wilson) Generate(m *Maze, r *rand.Rand, step StepFunc) {
+	m.Cells[r.Intn(m.Rows)][r.Intn(m.Cols)].In = true
+
+	// next holds the direction the walk last left each cell in, overwriting
+	// it when the walk revisits a cell is what erases the loops
+	next := make(map[CellIndex]CellIndex)
+	for row := 0; row < m.Rows; row++ {
+		for c := 0; c < m.Cols; c++ {
+			if m.Cells[row][c].In {
+				continue
+			}
+
+			current := CellIndex{Row: row, Col: c}
+			for !m.Cells[current.Row][current.Col].In {
+				neighbours := m.neighbours(current.Row, current.Col)
+				to := neighbours[r.Intn(len(neighbours))]
+				next[current] = to
+				current = to
+			}
+
+			// carve the loop free path, link marks cells as in so check where the walk ended first
+			current = CellIndex{Row: row, Col: c}
+			for {
+				to := next[current]
+				reached := m.Cells[to.Row][to.Col].In
+				m.link(current, to)
+				step.notify(m, current, to, func() []CellIndex {
+					// to has just been carved, the rest of the walk goes on from where it left to
+					if reached {
+						return nil
+					}
+
+					return walkFrom(next, next[to], m)
+				})
+				if reached {
+					break
+				}
+
+				current = to
+			}
+
+			for k := range next {
+				delete(next, k)
+			}
+		}
+	}
+}
+
+// walkFrom returns the cells of the loop erased walk from c that are not yet carved.
+func walkFrom(next map[CellIndex]CellIndex, c CellIndex, m *Maze) []CellIndex {
+	walk := make([]CellIndex, 0)
+	for !m.Cells[c.Row][c.Col].In {
+		walk = append(walk, c)
+		c = next[c]
+	}
+
+	return walk
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cg14823/gomaze/maze"
+)
+
+// generateMaze creates a new maze with opts, writing an animation of it being generated to animateGeneration
+// when set.
+func generateMaze(rows, cols int, opts []maze.Option, animateGeneration string, animateEvery int) (*maze.Maze,
+	error) {
+	var animation *maze.GenerationAnimation
+	if animateGeneration != "" {
+		if animateEvery == 0 {
+			animateEvery = rows * cols / 200
+		}
+
+		animation = maze.NewGenerationAnimation(animateEvery, 5)
+		opts = append(opts, maze.WithStepFunc(animation.Step))
+	}
+
+	m := maze.NewMaze(rows, cols, opts...)
+	fmt.Fprintln(os.Stderr, "Seed:", m.Seed)
+
+	if animation != nil {
+		err := writeOutput(animateGeneration, func(w io.Writer) error {
+			return animation.WriteGIF(w, m)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintln(os.Stderr, "Generation animation written to", animateGeneration)
+	}
+
+	return m, nil
+}
+
+// loadMaze reads a maze from path, or stdin for -. It can be saved by saveMaze, drawn as ASCII art or a PNG
+// image, which is told apart by its first bytes.
+func loadMaze(path string, pitch int) (*maze.Maze, error) {
+	in := io.Reader(os.Stdin)
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not load maze: %w", err)
+		}
+		defer f.Close()
+
+		in = f
+	}
+
+	r := bufio.NewReader(in)
+	head, _ := r.Peek(512)
+	var m *maze.Maze
+	var err error
+	switch {
+	case bytes.HasPrefix(head, []byte("GMZB")):
+		m, err = maze.ReadBinary(r)
+	case bytes.HasPrefix(head, []byte("\x89PNG")):
+		img, decodeErr := png.Decode(r)
+		if decodeErr != nil {
+			return nil, fmt.Errorf("could not load maze: %w", decodeErr)
+		}
+
+		m, err = maze.FromImage(img, maze.ImageOptions{Pitch: pitch})
+	case bytes.HasPrefix(bytes.TrimSpace(head), []byte("{")):
+		return maze.Load(r)
+	default:
+		m, err = maze.ParseASCII(r)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not load maze: %w", err)
+	}
+
+	return m, nil
+}
+
+// loadImage decodes the PNG image at path.
+func loadImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not load image: %w", err)
+	}
+	defer f.Close()
+
+	img, err := png.Decode(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("could not load image %s: %w", path, err)
+	}
+
+	return img, nil
+}
+
+// saveMaze writes m to path, or stdout for -, as compact binary when path ends in .bin and JSON otherwise.
+func saveMaze(path string, m *maze.Maze) error {
+	err := writeOutput(path, func(w io.Writer) error {
+		if strings.EqualFold(filepath.Ext(path), ".bin") {
+			return m.WriteBinary(w)
+		}
+
+		return m.Save(w)
+	})
+	if err != nil {
+		return fmt.Errorf("could not save maze: %w", err)
+	}
+
+	return nil
+}
+
+// writeOutput calls write with the file at path, creating the directories leading to it, or stdout for -.
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = write(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
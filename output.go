@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/cg14823/gomaze/maze"
+	"github.com/cg14823/gomaze/pathfinding"
+)
+
+// outputFormat is what writeImage draws the maze as.
+type outputFormat struct {
+	name      string
+	textStyle maze.TextStyle
+	termWidth int
+}
+
+// writeImage draws the maze and the paths, each in the colour at the same position, to fileOut in format.
+// Text has no colours so every path gets its own mark instead.
+func writeImage(m *maze.Maze, fileOut string, format outputFormat, paths [][]*maze.CellIndex,
+	colours []color.Color) error {
+	err := writeOutput(fileOut, func(w io.Writer) error {
+		switch format.name {
+		case "text":
+			return m.WriteText(w, maze.TextOptions{
+				Style: format.textStyle,
+				Paths: paths,
+			})
+		case "ansi":
+			return m.WriteANSI(w, maze.ANSIOptions{
+				Width:   format.termWidth,
+				Paths:   paths,
+				Colours: colours,
+			})
+		case "svg":
+			styles := make([]maze.PathStyle, len(paths))
+			for i := range paths {
+				// every path is thinner than the one before so overlapping paths can still be told apart
+				styles[i] = maze.PathStyle{
+					Colour: colours[i],
+					Width:  0.6 / float64(i+1),
+				}
+			}
+
+			return m.WriteSVG(w, paths, styles)
+		default:
+			return m.WritePNG(w, paths, colours)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("could not create image: %w", err)
+	}
+
+	if fileOut != "-" {
+		fmt.Fprintln(os.Stderr, "Image written to", fileOut)
+	}
+
+	return nil
+}
+
+// terminalWidth returns the number of columns of the terminal as exported by the shell in $COLUMNS, or 80.
+func terminalWidth() int {
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || width < 1 {
+		return 80
+	}
+
+	return width
+}
+
+// saveSearchAnimation writes the recorded searches as an animated GIF to fileOut, when every is 0 it is picked
+// so the longest search takes around 200 frames.
+func saveSearchAnimation(m *maze.Maze, fileOut string, every int, recorders []*pathfinding.Recorder) error {
+	pickEvery := every == 0
+	recordings := make([]maze.SearchRecording, len(recorders))
+	for i, r := range recorders {
+		recordings[i] = r.Recording()
+
+		// roughly half of the events are cells being expanded
+		if n := len(recordings[i].Events) / 2 / 200; pickEvery && n > every {
+			every = n
+		}
+	}
+
+	err := writeOutput(fileOut, func(w io.Writer) error {
+		return m.WriteSearchGIF(w, recordings, every, 5)
+	})
+	if err != nil {
+		return fmt.Errorf("could not create gif: %w", err)
+	}
+
+	fmt.Fprintln(os.Stderr, "Search animation written to", fileOut)
+	return nil
+}
+
+// printResult prints how the solver algo did on m to stderr, with the cost of the path when m is weighted
+// and the length of the route walked by the solvers that walk the maze and the cells filled by dead end filling.
+func printResult(m *maze.Maze, algo string, result *pathfinding.Result) {
+	fmt.Fprintf(os.Stderr, "Path found using %s took %d steps visiting %d cells in %s path length %d", algo,
+		result.Steps, result.Visited, result.Elapsed, len(result.Path))
+	if m.Weighted() {
+		fmt.Fprintf(os.Stderr, " cost %d", result.Cost)
+	}
+
+	if result.Route != nil {
+		fmt.Fprintf(os.Stderr, " walking %d cells", len(result.Route))
+	}
+
+	if result.Filled != nil {
+		fmt.Fprintf(os.Stderr, " filling %d cells", len(result.Filled))
+	}
+
+	fmt.Fprintln(os.Stderr)
+}
+
+// newLogTracer returns a tracer that logs the search of algo to stderr, or nil when not tracing.
+func newLogTracer(algo string, trace bool) *pathfinding.Tracer {
+	if !trace {
+		return nil
+	}
+
+	var expanded int
+	return &pathfinding.Tracer{
+		OnExpand: func(c maze.CellIndex) {
+			expanded++
+			fmt.Fprintf(os.Stderr, "%s: expand %d row %d col %d\n", algo, expanded, c.Row, c.Col)
+		},
+		OnFound: func(path []*maze.CellIndex) {
+			fmt.Fprintf(os.Stderr, "%s: found path of length %d\n", algo, len(path))
+		},
+	}
+}
+
+// searchTracer returns the tracer to run the solver algo with, logging the search when tracing and recording
+// it when animating, and the recorder, nil when not animating. A recording keeps every cell the search
+// looks at so it is only made when the animation is asked for.
+func (a *animationFlags) searchTracer(algo string) (*pathfinding.Tracer, *pathfinding.Recorder) {
+	tracer := newLogTracer(algo, a.trace)
+	if a.fileOut == "" {
+		return tracer, nil
+	}
+
+	recorder := &pathfinding.Recorder{}
+	return pathfinding.MultiTracer(tracer, recorder.Tracer()), recorder
+}
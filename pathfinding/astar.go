@@ -1,37 +1,91 @@
 package pathfinding
 
 import (
+	"container/heap"
 	"fmt"
+	"math"
 
 	"github.com/cg14823/gomaze/maze"
 )
 
 type AStartSearchCell struct {
 	Parent *AStartSearchCell
-	f      uint64
-	h      uint64
+	f      float64
+	h      float64
 	g      uint64
 
 	cell  *maze.Cell
 	index *maze.CellIndex
+	// heapIndex is the position of the cell in the open set, it is needed to
+	// update the priority of a cell when a shorter way to it is found
+	heapIndex int
 }
 
+// Heuristic estimates the cost of moving from a cell to the goal. For A* to
+// return a cheapest path it must never overestimate that cost, as every cell
+// costs at least 1, distances in cells never do.
+type Heuristic func(current, goal *maze.CellIndex) float64
+
+// Manhattan is the number of moves between the cells if there were no walls.
+func Manhattan(current, goal *maze.CellIndex) float64 {
+	return math.Abs(float64(goal.Row-current.Row)) + math.Abs(float64(goal.Col-current.Col))
+}
+
+// Euclidean is the straight line distance between the cells.
+func Euclidean(current, goal *maze.CellIndex) float64 {
+	return math.Hypot(float64(goal.Row-current.Row), float64(goal.Col-current.Col))
+}
+
+// Zero makes A* behave like Dijkstra's algorithm, also known as uniform cost search.
+func Zero(current, goal *maze.CellIndex) float64 {
+	return 0
+}
+
+func init() {
+	Register("astar", NewAstar(Manhattan))
+	Register("astar-euclidean", NewAstar(Euclidean))
+	Register("dijkstra", NewAstar(Zero))
+}
+
+// NewAstar returns a Solver that runs A* guided by h. Moving into a cell costs
+// maze.Maze.Cost so on weighted mazes the cheapest path is found rather than
+// the shortest.
+func NewAstar(h Heuristic) Solver {
+	return searchFunc(func(m *maze.Maze, t *Tracer) ([]*maze.CellIndex, uint64, int, error) {
+		return astarSearch(m, h, t)
+	})
+}
+
+// Astart runs A* using the Manhattan distance as heuristic.
 func Astart(m *maze.Maze) ([]*maze.CellIndex, uint64, error) {
-	out, steps, err := astart(m)
+	return AstarWithHeuristic(m, Manhattan)
+}
+
+// AstarWithHeuristic runs A* guided by h.
+func AstarWithHeuristic(m *maze.Maze, h Heuristic) ([]*maze.CellIndex, uint64, error) {
+	path, steps, _, err := astarSearch(m, h, nil)
+	return path, steps, err
+}
+
+func astarSearch(m *maze.Maze, h Heuristic, t *Tracer) ([]*maze.CellIndex, uint64, int, error) {
+	closed := newVisitedSet(m)
+	out, steps, err := astart(m, h, closed, t)
 	if err != nil {
-		return nil, steps, err
+		return nil, steps, closed.count, err
 	}
 
 	path := constructPath(out)
-	return path, steps, nil
+	t.found(path)
+	return path, steps, closed.count, nil
 }
 
-func astart(m *maze.Maze) (*AStartSearchCell, uint64, error) {
-	openSet := make([]*AStartSearchCell, 0)
-	inOpenList := make(map[maze.CellIndex]struct{})
-	h := heuristic(&m.End, &m.Start)
+func astart(m *maze.Maze, heuristic Heuristic, closed *visitedSet,
+	t *Tracer) (*AStartSearchCell, uint64, error) {
+	openSet := make(openSet, 0)
+	inOpenList := make(map[maze.CellIndex]*AStartSearchCell)
+	h := heuristic(&m.Start, &m.End)
 
-	start := AStartSearchCell{
+	start := &AStartSearchCell{
 		Parent: nil,
 		f:      h,
 		h:      h,
@@ -40,158 +94,103 @@ func astart(m *maze.Maze) (*AStartSearchCell, uint64, error) {
 	}
 
 	var step uint64
-	openSet = append(openSet, &start)
-	inOpenList[*start.index] = struct{}{}
-	for len(openSet) > 0 {
+	heap.Push(&openSet, start)
+	inOpenList[*start.index] = start
+	t.enqueue(start.index)
+	for openSet.Len() > 0 {
 		step++
-		current := openSet[0]
-		openSet = openSet[1:]
-
-		fmt.Printf("Step: %d, openSet size: %d current: %+v\n", step, len(openSet), current.index)
-
+		current := heap.Pop(&openSet).(*AStartSearchCell)
 		delete(inOpenList, *current.index)
-		m.VisitCell(current.index.Row, current.index.Col)
+
+		closed.visit(current.index)
+		t.expand(current.index)
 		if current.index.Equal(&m.End) {
 			return current, step, nil
 		}
 
-		adjacent := getAdjacent(current.index, &m.End, m, current.g)
+		adjacent := getAdjacent(current, &m.End, m, heuristic)
 		for _, c := range adjacent {
-			if m.Cells[c.index.Row][c.index.Col].Visited {
+			if closed.has(c.index) {
 				continue
 			}
 
-			_, ok := inOpenList[*c.index]
-			if ok {
-				ix := findInSorted(openSet, c.index)
-				if ix == -1 {
-					fmt.Println("This should not happen")
-					continue
-				}
-
-				if openSet[ix].g < c.g {
-					continue
-				}
+			open, ok := inOpenList[*c.index]
+			if !ok {
+				heap.Push(&openSet, c)
+				inOpenList[*c.index] = c
+				t.enqueue(c.index)
+				continue
+			}
 
-				// remove that entry from the openSet and replace it by the new one
-				openSet = append(openSet[:ix], openSet[ix+1:]...)
+			if open.g <= c.g {
+				continue
 			}
 
-			c.Parent = current
-			openSet = sortedInsert(openSet, c)
-			inOpenList[*c.index] = struct{}{}
+			// a cheaper way to a cell already in the open set, decrease its key
+			open.Parent = current
+			open.g = c.g
+			open.f = c.f
+			heap.Fix(&openSet, open.heapIndex)
 		}
 	}
 
 	return nil, step, fmt.Errorf("could not find path")
 }
 
-// heuristic is based on the straight line distance from the cell to the goal
-func heuristic(goal *maze.CellIndex, current *maze.CellIndex) uint64 {
-	return uint64((goal.Row-current.Row)*(goal.Row-current.Row) + (goal.Col-current.Col)*(goal.Col-current.Col))
-}
-
-func findInSorted(open []*AStartSearchCell, cellIndex *maze.CellIndex) int {
-	for i, c := range open {
-		if c.index.Equal(cellIndex) {
-			return i
-		}
-	}
+// openSet is a min heap of cells ordered by f, ties are broken by the larger g
+// as those cells are closer to the goal.
+type openSet []*AStartSearchCell
 
-	return -1
+func (o openSet) Len() int {
+	return len(o)
 }
 
-func sortedInsert(open []*AStartSearchCell, insert *AStartSearchCell) []*AStartSearchCell {
-	var insertIx int
-	for i, c := range open {
-		insertIx = i
-		if c.f > insert.f {
-			break
-		}
-	}
-
-	switch insertIx {
-	case 0:
-		open = append([]*AStartSearchCell{insert}, open...)
-	case len(open) - 1:
-		open = append(open, insert)
-	default:
-		open = append(append(open[:insertIx], insert), open[insertIx:]...)
+func (o openSet) Less(i, j int) bool {
+	if o[i].f == o[j].f {
+		return o[i].g > o[j].g
 	}
 
-	return open
+	return o[i].f < o[j].f
 }
 
-func getAdjacent(current, goal *maze.CellIndex, m *maze.Maze, g uint64) []*AStartSearchCell {
-	searchCells := make([]*AStartSearchCell, 0)
-	cell := m.Cells[current.Row][current.Col]
-
-	if cell.Top {
-		cell := &AStartSearchCell{
-			cell: &m.Cells[current.Row-1][current.Col],
-			index: &maze.CellIndex{
-				Col: current.Col,
-				Row: current.Row - 1,
-			},
-		}
-
-		h := heuristic(goal, cell.index)
-		cell.h = h
-		cell.g = g + 1
-		cell.f = g + h + 1
-
-		searchCells = append(searchCells, cell)
-	}
-
-	if cell.Bottom {
-		cell := &AStartSearchCell{
-			cell: &m.Cells[current.Row+1][current.Col],
-			index: &maze.CellIndex{
-				Col: current.Col,
-				Row: current.Row + 1,
-			},
-		}
-
-		h := heuristic(goal, cell.index)
-		cell.h = h
-		cell.g = g + 1
-		cell.f = g + h + 1
-
-		searchCells = append(searchCells, cell)
-	}
-
-	if cell.Left {
-		cell := &AStartSearchCell{
-			cell: &m.Cells[current.Row][current.Col-1],
-			index: &maze.CellIndex{
-				Col: current.Col - 1,
-				Row: current.Row,
-			},
-		}
-
-		h := heuristic(goal, cell.index)
-		cell.h = h
-		cell.g = g + 1
-		cell.f = g + h + 1
-
-		searchCells = append(searchCells, cell)
-	}
+func (o openSet) Swap(i, j int) {
+	o[i], o[j] = o[j], o[i]
+	o[i].heapIndex = i
+	o[j].heapIndex = j
+}
 
-	if cell.Right {
-		cell := &AStartSearchCell{
-			cell: &m.Cells[current.Row][current.Col+1],
-			index: &maze.CellIndex{
-				Col: current.Col + 1,
-				Row: current.Row,
-			},
-		}
+func (o *openSet) Push(x interface{}) {
+	c := x.(*AStartSearchCell)
+	c.heapIndex = len(*o)
+	*o = append(*o, c)
+}
 
-		h := heuristic(goal, cell.index)
-		cell.h = h
-		cell.g = g + 1
-		cell.f = g + h + 1
+func (o *openSet) Pop() interface{} {
+	old := *o
+	n := len(old)
+	c := old[n-1]
+	old[n-1] = nil
+	c.heapIndex = -1
+	*o = old[:n-1]
+	return c
+}
 
-		searchCells = append(searchCells, cell)
+func getAdjacent(current *AStartSearchCell, goal *maze.CellIndex, m *maze.Maze,
+	heuristic Heuristic) []*AStartSearchCell {
+	searchCells := make([]*AStartSearchCell, 0, 4)
+	neighbours := openNeighbours(m, *current.index)
+	for i := range neighbours {
+		n := &neighbours[i]
+		h := heuristic(n, goal)
+		g := current.g + uint64(m.Cost(*n))
+		searchCells = append(searchCells, &AStartSearchCell{
+			Parent: current,
+			f:      float64(g) + h,
+			h:      h,
+			g:      g,
+			cell:   &m.Cells[n.Row][n.Col],
+			index:  n,
+		})
 	}
 
 	return searchCells
@@ -0,0 +1,141 @@
+package pathfinding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cg14823/gomaze/maze"
+)
+
+// cheapestCost returns the cost of the cheapest path from the start to the end of m, found by relaxing every
+// passage until no cell gets any cheaper. It is slow but shares nothing with the solvers it checks.
+func cheapestCost(m *maze.Maze) int {
+	const unreached = -1
+	cost := make([][]int, m.Rows)
+	for r := range cost {
+		cost[r] = make([]int, m.Cols)
+		for c := range cost[r] {
+			cost[r][c] = unreached
+		}
+	}
+
+	cost[m.Start.Row][m.Start.Col] = 0
+	for changed := true; changed; {
+		changed = false
+		for r := range cost {
+			for c := range cost[r] {
+				if cost[r][c] == unreached {
+					continue
+				}
+
+				for _, n := range openNeighbours(m, maze.CellIndex{Row: r, Col: c}) {
+					through := cost[r][c] + m.Cost(n)
+					if cost[n.Row][n.Col] == unreached || through < cost[n.Row][n.Col] {
+						cost[n.Row][n.Col] = through
+						changed = true
+					}
+				}
+			}
+		}
+	}
+
+	return cost[m.End.Row][m.End.Col]
+}
+
+func TestAstarOptimal(t *testing.T) {
+	mazes := []struct {
+		name string
+		opts []maze.Option
+	}{
+		{"perfect", nil},
+		{"braided", []maze.Option{maze.WithBraid(1)}},
+		{"extra passages", []maze.Option{maze.WithExtraPassages(0.2)}},
+		{"weighted", []maze.Option{maze.WithRandomCosts(9)}},
+		{"braided weighted", []maze.Option{maze.WithBraid(0.5), maze.WithRandomCosts(20)}},
+		{"open weighted", []maze.Option{maze.WithExtraPassages(0.6), maze.WithRandomCosts(maze.MaxCost)}},
+	}
+
+	placements := []maze.Placement{
+		maze.RandomBorder{},
+		maze.OppositeCorners{},
+		maze.LongestPath{},
+		maze.FixedPlacement{Start: maze.CellIndex{Row: 7, Col: 3}, End: maze.CellIndex{Row: 2, Col: 19}},
+	}
+
+	for _, tt := range mazes {
+		for i, placement := range placements {
+			for seed := int64(0); seed < 10; seed++ {
+				opts := append([]maze.Option{maze.WithSeed(seed), maze.WithPlacement(placement)}, tt.opts...)
+				m := maze.NewMaze(15, 25, opts...)
+				want := cheapestCost(m)
+				names := []string{"astar", "astar-euclidean", "dijkstra", "biastar"}
+				if !m.Weighted() {
+					// bibfs ignores the costs, on weighted mazes it is compared with BFS instead
+					names = append(names, "bibfs")
+				}
+
+				for _, name := range names {
+					t.Run(fmt.Sprintf("%s/placement %d/seed %d/%s", tt.name, i, seed, name), func(t *testing.T) {
+						solver, err := Get(name)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						result, err := solver.Solve(m, nil)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						checkPath(t, m, result.Path)
+						if cost := m.PathCost(result.Path); cost != want || result.Cost != want {
+							t.Fatalf("path costs %d and the result says %d, the cheapest costs %d", cost,
+								result.Cost, want)
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+func TestBidirectionalBFSShortest(t *testing.T) {
+	mazes := []struct {
+		name string
+		opts []maze.Option
+	}{
+		{"perfect", nil},
+		{"braided", []maze.Option{maze.WithBraid(1)}},
+		{"weighted", []maze.Option{maze.WithRandomCosts(9)}},
+		{"braided weighted", []maze.Option{maze.WithBraid(0.5), maze.WithRandomCosts(maze.MaxCost)}},
+		{"open weighted", []maze.Option{maze.WithExtraPassages(0.6), maze.WithRandomCosts(20)}},
+	}
+
+	for _, tt := range mazes {
+		for seed := int64(0); seed < 20; seed++ {
+			t.Run(fmt.Sprintf("%s/seed %d", tt.name, seed), func(t *testing.T) {
+				opts := append([]maze.Option{maze.WithSeed(seed)}, tt.opts...)
+				m := maze.NewMaze(15, 25, opts...)
+				want, _, err := BFS(m)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				solver, err := Get("bibfs")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				result, err := solver.Solve(m, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// the costs are ignored so the path is as short as the one BFS finds
+				checkPath(t, m, result.Path)
+				if len(result.Path) != len(want) {
+					t.Fatalf("path is %d cells long, BFS found %d", len(result.Path), len(want))
+				}
+			})
+		}
+	}
+}
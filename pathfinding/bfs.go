@@ -13,18 +13,30 @@ type SearchCell struct {
 	index *maze.CellIndex
 }
 
+func init() {
+	Register("bfs", searchFunc(bfsSearch))
+}
+
 func BFS(m *maze.Maze) ([]*maze.CellIndex, uint64, error) {
-	cell, steps, err := bfs(m)
+	path, steps, _, err := bfsSearch(m, nil)
+	return path, steps, err
+}
+
+func bfsSearch(m *maze.Maze, t *Tracer) ([]*maze.CellIndex, uint64, int, error) {
+	visited := newVisitedSet(m)
+	cell, steps, err := bfs(m, visited, t)
 	if err != nil {
-		return nil, steps, err
+		return nil, steps, visited.count, err
 	}
 
 	slice := SearchCellToSlice(cell)
-	return slice, steps, err
+	t.found(slice)
+	return slice, steps, visited.count, err
 }
 
-func bfs(m *maze.Maze) (*SearchCell, uint64, error) {
-	m.VisitCell(m.Start.Row, m.Start.Col)
+func bfs(m *maze.Maze, visited *visitedSet, t *Tracer) (*SearchCell, uint64, error) {
+	visited.visit(&m.Start)
+	t.enqueue(&m.Start)
 	start := SearchCell{
 		index: &m.Start,
 		cell:  &m.Cells[m.Start.Row][m.Start.Col],
@@ -38,15 +50,17 @@ func bfs(m *maze.Maze) (*SearchCell, uint64, error) {
 		current := queue[0]
 		queue = queue[1:]
 
+		t.expand(current.index)
 		if current.index.Equal(&m.End) {
 			return &current, steps, nil
 		}
 
 		// Get all adjacent edges
-		connected := getConnectedUnvisitedCells(current.index, m)
+		connected := getConnectedUnvisitedCells(current.index, m, visited)
 		for _, c := range connected {
 			c.Parent = &current
-			m.VisitCell(c.index.Row, c.index.Col)
+			visited.visit(c.index)
+			t.enqueue(c.index)
 			queue = append(queue, c)
 		}
 	}
@@ -54,11 +68,11 @@ func bfs(m *maze.Maze) (*SearchCell, uint64, error) {
 	return nil, steps, fmt.Errorf("no path could be found")
 }
 
-func getConnectedUnvisitedCells(current *maze.CellIndex, m *maze.Maze) []SearchCell {
+func getConnectedUnvisitedCells(current *maze.CellIndex, m *maze.Maze, visited *visitedSet) []SearchCell {
 	searchCells := make([]SearchCell, 0)
 	cell := m.Cells[current.Row][current.Col]
 
-	if cell.Top && !m.Cells[current.Row-1][current.Col].Visited {
+	if cell.Top && !visited.has(&maze.CellIndex{Col: current.Col, Row: current.Row - 1}) {
 		searchCells = append(searchCells, SearchCell{
 			cell: &m.Cells[current.Row-1][current.Col],
 			index: &maze.CellIndex{
@@ -68,7 +82,7 @@ func getConnectedUnvisitedCells(current *maze.CellIndex, m *maze.Maze) []SearchC
 		})
 	}
 
-	if cell.Bottom && !m.Cells[current.Row+1][current.Col].Visited {
+	if cell.Bottom && !visited.has(&maze.CellIndex{Col: current.Col, Row: current.Row + 1}) {
 		searchCells = append(searchCells, SearchCell{
 			cell: &m.Cells[current.Row+1][current.Col],
 			index: &maze.CellIndex{
@@ -78,7 +92,7 @@ func getConnectedUnvisitedCells(current *maze.CellIndex, m *maze.Maze) []SearchC
 		})
 	}
 
-	if cell.Left && !m.Cells[current.Row][current.Col-1].Visited {
+	if cell.Left && !visited.has(&maze.CellIndex{Col: current.Col - 1, Row: current.Row}) {
 		searchCells = append(searchCells, SearchCell{
 			cell: &m.Cells[current.Row][current.Col-1],
 			index: &maze.CellIndex{
@@ -88,7 +102,7 @@ func getConnectedUnvisitedCells(current *maze.CellIndex, m *maze.Maze) []SearchC
 		})
 	}
 
-	if cell.Right && !m.Cells[current.Row][current.Col+1].Visited {
+	if cell.Right && !visited.has(&maze.CellIndex{Col: current.Col + 1, Row: current.Row}) {
 		searchCells = append(searchCells, SearchCell{
 			cell: &m.Cells[current.Row][current.Col+1],
 			index: &maze.CellIndex{
@@ -0,0 +1,322 @@
+package pathfinding
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+
+	"github.com/cg14823/gomaze/maze"
+)
+
+func init() {
+	Register("bibfs", searchFunc(bidirectionalBFSSearch))
+	Register("biastar", NewBidirectionalAstar(Manhattan))
+}
+
+// the sides of a bidirectional search
+const (
+	fromStart = iota
+	fromEnd
+)
+
+// the way back from a cell to the one it was found from in a bidirectional BFS, 0 is a cell not found yet
+const (
+	backNone uint8 = iota
+	backRoot
+	backUp
+	backDown
+	backLeft
+	backRight
+)
+
+// BidirectionalBFS runs a BFS from the start and another from the end at the same time, a layer at a time
+// from whichever has the smaller frontier, and stops when they meet. It finds a shortest path like BFS while
+// looking at far fewer cells on big mazes.
+func BidirectionalBFS(m *maze.Maze) ([]*maze.CellIndex, uint64, error) {
+	path, steps, _, err := bidirectionalBFSSearch(m, nil)
+	return path, steps, err
+}
+
+func bidirectionalBFSSearch(m *maze.Maze, t *Tracer) ([]*maze.CellIndex, uint64, int, error) {
+	visited := newVisitedSet(m)
+	// a byte per cell and side keeps huge mazes affordable, the parents are found again from the way back
+	back := [2][]uint8{
+		make([]uint8, m.Rows*m.Cols),
+		make([]uint8, m.Rows*m.Cols),
+	}
+
+	frontiers := [2][]maze.CellIndex{{m.Start}, {m.End}}
+	for side, c := range []maze.CellIndex{m.Start, m.End} {
+		back[side][c.GetID(m.Cols)] = backRoot
+		visited.visit(&c)
+		t.enqueue(&c)
+	}
+
+	if m.Start == m.End {
+		path := []*maze.CellIndex{&m.Start}
+		t.found(path)
+		return path, 0, visited.count, nil
+	}
+
+	var steps uint64
+	for len(frontiers[fromStart]) > 0 && len(frontiers[fromEnd]) > 0 {
+		side := fromStart
+		if len(frontiers[fromEnd]) < len(frontiers[fromStart]) {
+			side = fromEnd
+		}
+
+		other := 1 - side
+		next := make([]maze.CellIndex, 0, len(frontiers[side]))
+		for i := range frontiers[side] {
+			steps++
+			current := frontiers[side][i]
+			t.expand(&current)
+			for _, n := range openNeighbours(m, current) {
+				id := n.GetID(m.Cols)
+				if back[side][id] != backNone {
+					continue
+				}
+
+				back[side][id] = backTowards(n, current)
+				visited.visit(&n)
+				t.enqueue(&n)
+
+				// as the frontiers grow a layer at a time the first cell both reach is on a shortest path
+				if back[other][id] != backNone {
+					path := joinPaths(walkBack(m, back[fromStart], n), walkBack(m, back[fromEnd], n))
+					t.found(path)
+					return path, steps, visited.count, nil
+				}
+
+				next = append(next, n)
+			}
+		}
+
+		frontiers[side] = next
+	}
+
+	return nil, steps, visited.count, fmt.Errorf("no path could be found")
+}
+
+// backTowards returns the way back from c to its neighbour parent.
+func backTowards(c, parent maze.CellIndex) uint8 {
+	switch {
+	case parent.Row < c.Row:
+		return backUp
+	case parent.Row > c.Row:
+		return backDown
+	case parent.Col < c.Col:
+		return backLeft
+	default:
+		return backRight
+	}
+}
+
+// walkBack follows the ways back from c to the root of its side, both included.
+func walkBack(m *maze.Maze, back []uint8, c maze.CellIndex) []*maze.CellIndex {
+	path := []*maze.CellIndex{&c}
+	for {
+		current := *path[len(path)-1]
+		switch back[current.GetID(m.Cols)] {
+		case backUp:
+			current.Row--
+		case backDown:
+			current.Row++
+		case backLeft:
+			current.Col--
+		case backRight:
+			current.Col++
+		default:
+			return path
+		}
+
+		path = append(path, &current)
+	}
+}
+
+// joinPaths joins the path from the meeting cell to the start with the one from the meeting cell to the end
+// into a path from the end to the start, like the other solvers return.
+func joinPaths(toStart, toEnd []*maze.CellIndex) []*maze.CellIndex {
+	path := make([]*maze.CellIndex, 0, len(toStart)+len(toEnd)-1)
+	for i := len(toEnd) - 1; i > 0; i-- {
+		path = append(path, toEnd[i])
+	}
+
+	return append(path, toStart...)
+}
+
+func openNeighbours(m *maze.Maze, c maze.CellIndex) []maze.CellIndex {
+	cell := m.Cells[c.Row][c.Col]
+	neighbours := make([]maze.CellIndex, 0, 4)
+	if cell.Top {
+		neighbours = append(neighbours, maze.CellIndex{Col: c.Col, Row: c.Row - 1})
+	}
+
+	if cell.Bottom {
+		neighbours = append(neighbours, maze.CellIndex{Col: c.Col, Row: c.Row + 1})
+	}
+
+	if cell.Left {
+		neighbours = append(neighbours, maze.CellIndex{Col: c.Col - 1, Row: c.Row})
+	}
+
+	if cell.Right {
+		neighbours = append(neighbours, maze.CellIndex{Col: c.Col + 1, Row: c.Row})
+	}
+
+	return neighbours
+}
+
+// NewBidirectionalAstar returns a Solver that runs an A* search from the start towards the end and another
+// from the end towards the start, expanding whichever has fewer open cells. Instead of h each side is guided by
+// half the difference between h to its goal and h to its own root, which makes both sides search the same
+// problem so they can stop as soon as the lowest f left open on each adds up to the cost of the cheapest path
+// through a cell both have reached. Like A* the cheapest path is found when h never overestimates.
+func NewBidirectionalAstar(h Heuristic) Solver {
+	return searchFunc(func(m *maze.Maze, t *Tracer) ([]*maze.CellIndex, uint64, int, error) {
+		return bidirectionalAstarSearch(m, h, t)
+	})
+}
+
+// BidirectionalAstar runs a bidirectional A* using the Manhattan distance as heuristic.
+func BidirectionalAstar(m *maze.Maze) ([]*maze.CellIndex, uint64, error) {
+	path, steps, _, err := bidirectionalAstarSearch(m, Manhattan, nil)
+	return path, steps, err
+}
+
+// astarSide is one of the two searches of a bidirectional A*.
+type astarSide struct {
+	open openSet
+	// seen holds every cell the side has reached, open or closed
+	seen   map[maze.CellIndex]*AStartSearchCell
+	closed *visitedSet
+	// potential replaces the heuristic, it can be negative
+	potential func(c *maze.CellIndex) float64
+}
+
+func newAstarSide(m *maze.Maze, from *maze.CellIndex, potential func(c *maze.CellIndex) float64,
+	t *Tracer) *astarSide {
+	s := &astarSide{
+		open:      make(openSet, 0),
+		seen:      make(map[maze.CellIndex]*AStartSearchCell),
+		closed:    newVisitedSet(m),
+		potential: potential,
+	}
+
+	root := &AStartSearchCell{
+		f:     potential(from),
+		h:     potential(from),
+		cell:  &m.Cells[from.Row][from.Col],
+		index: from,
+	}
+
+	heap.Push(&s.open, root)
+	s.seen[*from] = root
+	t.enqueue(from)
+	return s
+}
+
+// minF returns the lowest f of the open cells.
+func (s *astarSide) minF() float64 {
+	if s.open.Len() == 0 {
+		return math.Inf(1)
+	}
+
+	return s.open[0].f
+}
+
+func bidirectionalAstarSearch(m *maze.Maze, h Heuristic, t *Tracer) ([]*maze.CellIndex, uint64, int, error) {
+	// the potentials of the sides add up to 0 and are consistent when h is, which is what lets the search
+	// stop early, see Goldberg and Harrelson's "Computing the shortest path: A* search meets graph theory"
+	toEnd := func(c *maze.CellIndex) float64 {
+		return (h(c, &m.End) - h(c, &m.Start)) / 2
+	}
+
+	toStart := func(c *maze.CellIndex) float64 {
+		return -toEnd(c)
+	}
+
+	sides := [2]*astarSide{
+		newAstarSide(m, &m.Start, toEnd, t),
+		newAstarSide(m, &m.End, toStart, t),
+	}
+
+	if m.Start == m.End {
+		path := []*maze.CellIndex{&m.Start}
+		t.found(path)
+		return path, 0, 0, nil
+	}
+
+	expanded := newVisitedSet(m)
+	var meet *maze.CellIndex
+	var best uint64
+	var steps uint64
+	for sides[fromStart].open.Len() > 0 || sides[fromEnd].open.Len() > 0 {
+		if meet != nil && sides[fromStart].minF()+sides[fromEnd].minF() >= float64(best) {
+			break
+		}
+
+		side := fromStart
+		if sides[fromStart].open.Len() == 0 ||
+			(sides[fromEnd].open.Len() > 0 && sides[fromEnd].open.Len() < sides[fromStart].open.Len()) {
+			side = fromEnd
+		}
+
+		s, other := sides[side], sides[1-side]
+		steps++
+		current := heap.Pop(&s.open).(*AStartSearchCell)
+		s.closed.visit(current.index)
+		expanded.visit(current.index)
+		t.expand(current.index)
+
+		for _, n := range openNeighbours(m, *current.index) {
+			n := n
+			if s.closed.has(&n) {
+				continue
+			}
+
+			// moving from a into b costs the cost of b, searching backwards from b the move costs the same
+			cost := m.Cost(n)
+			if side == fromEnd {
+				cost = m.Cost(*current.index)
+			}
+
+			g := current.g + uint64(cost)
+			c, ok := s.seen[n]
+			switch {
+			case !ok:
+				c = &AStartSearchCell{
+					Parent: current,
+					h:      s.potential(&n),
+					cell:   &m.Cells[n.Row][n.Col],
+					index:  &n,
+				}
+				c.g = g
+				c.f = float64(g) + c.h
+				heap.Push(&s.open, c)
+				s.seen[n] = c
+				t.enqueue(&n)
+			case g < c.g:
+				c.Parent = current
+				c.g = g
+				c.f = float64(g) + c.h
+				heap.Fix(&s.open, c.heapIndex)
+			default:
+				continue
+			}
+
+			if o, ok := other.seen[n]; ok && (meet == nil || c.g+o.g < best) {
+				meet = c.index
+				best = c.g + o.g
+			}
+		}
+	}
+
+	if meet == nil {
+		return nil, steps, expanded.count, fmt.Errorf("could not find path")
+	}
+
+	path := joinPaths(constructPath(sides[fromStart].seen[*meet]), constructPath(sides[fromEnd].seen[*meet]))
+	t.found(path)
+	return path, steps, expanded.count, nil
+}
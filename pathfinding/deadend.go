@@ -0,0 +1,122 @@
+package pathfinding
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cg14823/gomaze/maze"
+)
+
+// ErrSeveralWays is returned by dead end filling when filling leaves more than one way to go, which happens
+// on mazes with loops as a loop has no dead end to fill from.
+var ErrSeveralWays = errors.New("dead end filling left more than one way to go")
+
+func init() {
+	Register("dead-end-fill", deadEndFilling{})
+}
+
+// DeadEndFilling fills every dead end of the maze other than the start and the end, and the cells that become
+// dead ends as they are filled, until none are left. On a perfect maze that leaves a single corridor from the
+// start to the end, which is walked to give the path. It returns the path like BFS and the cells filled in
+// order.
+func DeadEndFilling(m *maze.Maze) ([]*maze.CellIndex, []*maze.CellIndex, uint64, error) {
+	result, err := deadEndFilling{}.Solve(m, nil)
+	return result.Path, result.Filled, result.Steps, err
+}
+
+// deadEndFilling is a Solver that reports the cells it filled on top of the corridor it walked.
+type deadEndFilling struct{}
+
+func (deadEndFilling) Solve(m *maze.Maze, t *Tracer) (*Result, error) {
+	start := time.Now()
+	filled, isFilled := fillDeadEnds(m, t)
+	route, err := walkCorridor(m, isFilled, t)
+	result := &Result{
+		Route:   route,
+		Filled:  filled,
+		Steps:   uint64(len(filled) + len(route) - 1),
+		Visited: len(filled) + len(route),
+	}
+
+	if err == nil {
+		result.Path = make([]*maze.CellIndex, len(route))
+		for i, c := range route {
+			result.Path[len(route)-1-i] = c
+		}
+
+		result.Cost = m.PathCost(result.Path)
+		t.found(result.Path)
+	}
+
+	result.Elapsed = time.Since(start)
+	return result, err
+}
+
+// fillDeadEnds returns the cells filled in order and the set of them.
+func fillDeadEnds(m *maze.Maze, t *Tracer) ([]*maze.CellIndex, *visitedSet) {
+	isFilled := newVisitedSet(m)
+	openings := make([]uint8, m.Rows*m.Cols)
+	var deadEnds []maze.CellIndex
+	for row := range m.Cells {
+		for col := range m.Cells[row] {
+			c := maze.CellIndex{Row: row, Col: col}
+			openings[c.GetID(m.Cols)] = uint8(len(openNeighbours(m, c)))
+			if openings[c.GetID(m.Cols)] <= 1 && c != m.Start && c != m.End {
+				deadEnds = append(deadEnds, c)
+				t.enqueue(&c)
+			}
+		}
+	}
+
+	filled := make([]*maze.CellIndex, 0, len(deadEnds))
+	for len(deadEnds) > 0 {
+		c := deadEnds[len(deadEnds)-1]
+		deadEnds = deadEnds[:len(deadEnds)-1]
+		isFilled.visit(&c)
+		filled = append(filled, &c)
+		t.expand(&c)
+		for _, n := range openNeighbours(m, c) {
+			n := n
+			if isFilled.has(&n) {
+				continue
+			}
+
+			id := n.GetID(m.Cols)
+			openings[id]--
+			if openings[id] == 1 && n != m.Start && n != m.End {
+				deadEnds = append(deadEnds, n)
+				t.enqueue(&n)
+			}
+		}
+	}
+
+	return filled, isFilled
+}
+
+// walkCorridor walks from the start to the end through the cells left unfilled, returning the cells walked
+// from the start. It fails when there is more than one way to go or no way at all.
+func walkCorridor(m *maze.Maze, isFilled *visitedSet, t *Tracer) ([]*maze.CellIndex, error) {
+	c, previous := m.Start, m.Start
+	route := newRoute(c, t)
+	for c != m.End {
+		var ways []maze.CellIndex
+		for _, n := range openNeighbours(m, c) {
+			n := n
+			if n != previous && !isFilled.has(&n) {
+				ways = append(ways, n)
+			}
+		}
+
+		switch {
+		case len(ways) == 0:
+			return route, errors.New("could not find path")
+		case len(ways) > 1:
+			return route, ErrSeveralWays
+		}
+
+		previous, c = c, ways[0]
+		route = walkTo(route, c, t)
+	}
+
+	return route, nil
+}
@@ -6,18 +6,30 @@ import (
 	"github.com/cg14823/gomaze/maze"
 )
 
+func init() {
+	Register("dfs", searchFunc(dfsSearch))
+}
+
 func DFS(m *maze.Maze) ([]*maze.CellIndex, uint64, error) {
-	cell, steps, err := dfs(m)
+	path, steps, _, err := dfsSearch(m, nil)
+	return path, steps, err
+}
+
+func dfsSearch(m *maze.Maze, t *Tracer) ([]*maze.CellIndex, uint64, int, error) {
+	visited := newVisitedSet(m)
+	cell, steps, err := dfs(m, visited, t)
 	if err != nil {
-		return nil, steps, err
+		return nil, steps, visited.count, err
 	}
 
 	slice := SearchCellToSlice(cell)
-	return slice, steps, err
+	t.found(slice)
+	return slice, steps, visited.count, err
 }
 
-func dfs(m *maze.Maze) (*SearchCell, uint64, error) {
-	m.VisitCell(m.Start.Row, m.Start.Col)
+func dfs(m *maze.Maze, visited *visitedSet, t *Tracer) (*SearchCell, uint64, error) {
+	visited.visit(&m.Start)
+	t.enqueue(&m.Start)
 	start := SearchCell{
 		index: &m.Start,
 		cell:  &m.Cells[m.Start.Row][m.Start.Col],
@@ -31,14 +43,16 @@ func dfs(m *maze.Maze) (*SearchCell, uint64, error) {
 		current := stack[len(stack)-1]
 		stack = stack[:len(stack)-1]
 
+		t.expand(current.index)
 		if current.index.Equal(&m.End) {
 			return &current, steps, nil
 		}
 
-		connected := getConnectedUnvisitedCells(current.index, m)
+		connected := getConnectedUnvisitedCells(current.index, m, visited)
 		for _, c := range connected {
 			c.Parent = &current
-			m.VisitCell(c.index.Row, c.index.Col)
+			visited.visit(c.index)
+			t.enqueue(c.index)
 			stack = append(stack, c)
 		}
 	}
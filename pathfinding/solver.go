@@ -0,0 +1,127 @@
+package pathfinding
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cg14823/gomaze/maze"
+)
+
+// Result is the outcome of running a Solver on a maze.
+type Result struct {
+	// Path goes from the end of the maze back to the start.
+	Path []*maze.CellIndex
+	// Steps is the number of iterations the search loop ran for.
+	Steps uint64
+	// Visited is the number of cells the search looked at.
+	Visited int
+	// Cost is the cost of walking the path, see maze.Maze.PathCost.
+	Cost int
+	// Route is every cell walked through in order from the start, going back
+	// over dead ends and loops included. It is only set by the solvers that walk
+	// the maze like a person would, such as the wall followers, and by dead end
+	// filling for the corridor it walks once the dead ends are filled.
+	Route []*maze.CellIndex
+	// Filled are the cells dead end filling filled in, in order.
+	Filled  []*maze.CellIndex
+	Elapsed time.Duration
+}
+
+// Solver finds a path from the start to the end of a maze. A Result must be
+// returned even when no path is found so the work done can be inspected.
+// The progress of the search is reported to t, which may be nil.
+type Solver interface {
+	Solve(m *maze.Maze, t *Tracer) (*Result, error)
+}
+
+// SolverFunc adapts a function with the same signature as BFS to a Solver.
+// The function only reports its steps so Result.Visited is left as 0 and the
+// tracer is only told about the path found.
+type SolverFunc func(m *maze.Maze) ([]*maze.CellIndex, uint64, error)
+
+func (f SolverFunc) Solve(m *maze.Maze, t *Tracer) (*Result, error) {
+	start := time.Now()
+	path, steps, err := f(m)
+	if err == nil {
+		t.found(path)
+	}
+
+	result := &Result{
+		Path:    path,
+		Steps:   steps,
+		Cost:    m.PathCost(path),
+		Elapsed: time.Since(start),
+	}
+
+	return result, err
+}
+
+// searchFunc is the shape of the built in solvers, on top of what a SolverFunc
+// returns they report how many cells they visited. They keep their own visited
+// state and never write to the maze.
+type searchFunc func(m *maze.Maze, t *Tracer) ([]*maze.CellIndex, uint64, int, error)
+
+func (f searchFunc) Solve(m *maze.Maze, t *Tracer) (*Result, error) {
+	start := time.Now()
+	path, steps, visited, err := f(m, t)
+	result := &Result{
+		Path:    path,
+		Steps:   steps,
+		Visited: visited,
+		Cost:    m.PathCost(path),
+		Elapsed: time.Since(start),
+	}
+
+	return result, err
+}
+
+var (
+	solversMu sync.RWMutex
+	solvers   = make(map[string]Solver)
+)
+
+// Register makes a solver available by name. It panics if the name is already
+// taken or the solver is nil, as that can only be a programming error.
+func Register(name string, s Solver) {
+	solversMu.Lock()
+	defer solversMu.Unlock()
+
+	if s == nil {
+		panic("pathfinding: Register solver is nil")
+	}
+
+	if _, dup := solvers[name]; dup {
+		panic("pathfinding: Register called twice for solver " + name)
+	}
+
+	solvers[name] = s
+}
+
+// Get returns the solver registered under name.
+func Get(name string) (Solver, error) {
+	solversMu.RLock()
+	defer solversMu.RUnlock()
+
+	s, ok := solvers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown solver: %s", name)
+	}
+
+	return s, nil
+}
+
+// List returns the names of the registered solvers sorted alphabetically.
+func List() []string {
+	solversMu.RLock()
+	defer solversMu.RUnlock()
+
+	names := make([]string, 0, len(solvers))
+	for name := range solvers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,275 @@
+package pathfinding
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cg14823/gomaze/maze"
+)
+
+// checkPath fails t unless path goes from the end of m to its start through open passages only.
+func checkPath(t *testing.T, m *maze.Maze, path []*maze.CellIndex) {
+	t.Helper()
+	if len(path) == 0 {
+		t.Fatal("path is empty")
+	}
+
+	if *path[0] != m.End || *path[len(path)-1] != m.Start {
+		t.Fatalf("path goes from %+v to %+v, want from the end %+v to the start %+v", *path[0],
+			*path[len(path)-1], m.End, m.Start)
+	}
+
+	for i := 1; i < len(path); i++ {
+		if !adjacentOpen(m, *path[i-1], *path[i]) {
+			t.Fatalf("step %d of the path from %+v to %+v does not follow a passage", i, *path[i-1], *path[i])
+		}
+	}
+}
+
+// adjacentOpen reports whether there is a passage from a to b.
+func adjacentOpen(m *maze.Maze, a, b maze.CellIndex) bool {
+	for _, n := range openNeighbours(m, a) {
+		if n == b {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestSolversRectangular(t *testing.T) {
+	sizes := [][2]int{{1, 500}, {500, 3}, {3, 500}, {500, 1}}
+	for _, size := range sizes {
+		for _, generator := range maze.GeneratorNames() {
+			for _, name := range []string{"bfs", "dfs", "astar"} {
+				size, generator, name := size, generator, name
+				t.Run(fmt.Sprintf("%dx%d/%s/%s", size[0], size[1], generator, name), func(t *testing.T) {
+					g, _ := maze.GetGenerator(generator)
+					m := maze.NewMaze(size[0], size[1], maze.WithSeed(5), maze.WithGenerator(g),
+						maze.WithPlacement(maze.OppositeCorners{}))
+					solver, err := Get(name)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					result, err := solver.Solve(m, nil)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					checkPath(t, m, result.Path)
+
+					// opposite corners of a perfect maze this thin are at least as far apart as its length
+					if shortest := size[0] + size[1] - 1; len(result.Path) < shortest {
+						t.Fatalf("path has %d cells, the corners are at least %d apart", len(result.Path), shortest)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestSolverFunctionsRectangular(t *testing.T) {
+	for _, size := range [][2]int{{1, 500}, {500, 3}} {
+		m := maze.NewMaze(size[0], size[1], maze.WithSeed(5))
+		for name, f := range map[string]func(*maze.Maze) ([]*maze.CellIndex, uint64, error){
+			"BFS":    BFS,
+			"DFS":    DFS,
+			"Astart": Astart,
+		} {
+			path, _, err := f(m)
+			if err != nil {
+				t.Fatalf("%s on %dx%d: %v", name, size[0], size[1], err)
+			}
+
+			checkPath(t, m, path)
+		}
+	}
+}
+
+func TestDeadEndFilling(t *testing.T) {
+	placements := []maze.Placement{maze.RandomBorder{}, maze.OppositeCorners{}, maze.LongestPath{}}
+	for i, placement := range placements {
+		for seed := int64(0); seed < 10; seed++ {
+			t.Run(fmt.Sprintf("placement %d/seed %d", i, seed), func(t *testing.T) {
+				m := maze.NewMaze(15, 25, maze.WithSeed(seed), maze.WithPlacement(placement))
+				path, filled, _, err := DeadEndFilling(m)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				checkPath(t, m, path)
+				want, _, _ := BFS(m)
+				if len(path) != len(want) {
+					t.Fatalf("path is %d cells long, BFS found %d", len(path), len(want))
+				}
+
+				if len(filled)+len(path) != m.Rows*m.Cols {
+					t.Fatalf("filled %d cells and walked %d of %d", len(filled), len(path), m.Rows*m.Cols)
+				}
+
+				solver, err := Get("dead-end-fill")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				solved, err := solver.Solve(m, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(solved.Route) != len(path) || *solved.Route[0] != m.Start || len(solved.Filled) != len(filled) {
+					t.Fatalf("route has %d cells from %+v and %d filled, want %d from the start %+v and %d filled",
+						len(solved.Route), *solved.Route[0], len(solved.Filled), len(path), m.Start, len(filled))
+				}
+			})
+		}
+	}
+
+	m := maze.NewMaze(15, 25, maze.WithSeed(1), maze.WithBraid(1))
+	if _, _, _, err := DeadEndFilling(m); err != ErrSeveralWays {
+		t.Fatalf("got %v on a braided maze, want %v", err, ErrSeveralWays)
+	}
+}
+
+// checkRoute fails t unless route walks from the start of m to its end a cell at a time through open passages.
+func checkRoute(t *testing.T, m *maze.Maze, route []*maze.CellIndex) {
+	t.Helper()
+	if len(route) == 0 || *route[0] != m.Start || *route[len(route)-1] != m.End {
+		t.Fatalf("route of %d cells does not go from the start %+v to the end %+v", len(route), m.Start, m.End)
+	}
+
+	for i := 1; i < len(route); i++ {
+		if !adjacentOpen(m, *route[i-1], *route[i]) {
+			t.Fatalf("step %d of the route from %+v to %+v does not follow a passage", i, *route[i-1], *route[i])
+		}
+	}
+}
+
+// walker is the shape of the exported solvers that walk through the maze.
+type walker func(m *maze.Maze) ([]*maze.CellIndex, []*maze.CellIndex, uint64, error)
+
+func TestWalkers(t *testing.T) {
+	walkers := []struct {
+		name string
+		f    walker
+	}{
+		{"wall-left", LeftHandWallFollower},
+		{"wall-right", RightHandWallFollower},
+		{"pledge", Pledge},
+		{"tremaux", Tremaux},
+	}
+
+	placements := []maze.Placement{maze.RandomBorder{}, maze.OppositeCorners{}, maze.LongestPath{}}
+	for _, w := range walkers {
+		for _, generator := range maze.GeneratorNames() {
+			for i, placement := range placements {
+				for seed := int64(0); seed < 5; seed++ {
+					t.Run(fmt.Sprintf("%s/%s/placement %d/seed %d", w.name, generator, i, seed), func(t *testing.T) {
+						g, err := maze.GetGenerator(generator)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						m := maze.NewMaze(12, 17, maze.WithGenerator(g), maze.WithPlacement(placement),
+							maze.WithSeed(seed))
+						path, route, steps, err := w.f(m)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						checkRoute(t, m, route)
+						checkPath(t, m, path)
+						want, _, _ := BFS(m)
+						if len(path) != len(want) || steps != uint64(len(route)-1) {
+							t.Fatalf("path is %d cells long after %d steps walking %d cells, BFS found %d",
+								len(path), steps, len(route), len(want))
+						}
+
+						solver, err := Get(w.name)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						result, err := solver.Solve(m, nil)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						if len(result.Route) != len(route) || result.Cost != m.PathCost(path) {
+							t.Fatalf("solver walked %d cells for a path costing %d, want %d and %d",
+								len(result.Route), result.Cost, len(route), m.PathCost(path))
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+func TestWalkersAroundPillar(t *testing.T) {
+	// the start is on the left and on the right of a pillar in the middle of a ring of passages, a wall
+	// follower with that hand on the pillar circles it forever
+	tests := []struct {
+		name  string
+		maze  string
+		lost  walker
+		found walker
+	}{
+		{"left hand on the pillar", `
++--+--+--+--+--+
+|              |
++  +--+--+  +  +
+|  |        |  |
++  +  +--+  +  +
+|  |S |  |  |  |
++  +  +--+  +  +
+|  |        |  |
++  +--+--+--+  +
+|            E |
++--+--+--+--+--+
+`, LeftHandWallFollower, RightHandWallFollower},
+		{"right hand on the pillar", `
++--+--+--+--+--+
+|              |
++  +  +--+--+  +
+|  |        |  |
++  +  +--+  +  +
+|  |  |  |S |  |
++  +  +--+  +  +
+|  |        |  |
++  +--+--+--+  +
+| E            |
++--+--+--+--+--+
+`, RightHandWallFollower, LeftHandWallFollower},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := maze.ParseASCII(strings.NewReader(tt.maze))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, route, _, err := tt.lost(m)
+			if err != ErrWalkingInLoop {
+				t.Fatalf("got error %v, want %v", err, ErrWalkingInLoop)
+			}
+
+			if len(route) == 0 || *route[0] != m.Start {
+				t.Fatalf("route of %d cells does not start at the start %+v", len(route), m.Start)
+			}
+
+			for _, f := range []walker{tt.found, Pledge, Tremaux} {
+				path, route, _, err := f(m)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				checkRoute(t, m, route)
+				checkPath(t, m, path)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package pathfinding
+
+import "github.com/cg14823/gomaze/maze"
+
+// Tracer is notified as a search progresses, it can be used to log, count or
+// draw what a solver is doing. Any of the callbacks can be left nil and a nil
+// *Tracer traces nothing.
+type Tracer struct {
+	// OnEnqueue is called when a cell is added to the frontier of the search,
+	// the queue for BFS, the stack for DFS and the open set for A*.
+	OnEnqueue func(c maze.CellIndex)
+	// OnExpand is called when a cell is taken off the frontier to look at its neighbours.
+	OnExpand func(c maze.CellIndex)
+	// OnFound is called with the path once the end of the maze is reached.
+	OnFound func(path []*maze.CellIndex)
+}
+
+func (t *Tracer) enqueue(c *maze.CellIndex) {
+	if t != nil && t.OnEnqueue != nil {
+		t.OnEnqueue(*c)
+	}
+}
+
+func (t *Tracer) expand(c *maze.CellIndex) {
+	if t != nil && t.OnExpand != nil {
+		t.OnExpand(*c)
+	}
+}
+
+func (t *Tracer) found(path []*maze.CellIndex) {
+	if t != nil && t.OnFound != nil {
+		t.OnFound(path)
+	}
+}
+
+// Recorder keeps every event of a search so it can be replayed, for example
+// with maze.WriteSearchGIF.
+type Recorder struct {
+	recording maze.SearchRecording
+}
+
+// Tracer returns the tracer to hand to the solver being recorded.
+func (r *Recorder) Tracer() *Tracer {
+	return &Tracer{
+		OnEnqueue: func(c maze.CellIndex) {
+			r.recording.Events = append(r.recording.Events, maze.SearchEvent{
+				Kind: maze.SearchEnqueue,
+				Cell: c,
+			})
+		},
+		OnExpand: func(c maze.CellIndex) {
+			r.recording.Events = append(r.recording.Events, maze.SearchEvent{
+				Kind: maze.SearchExpand,
+				Cell: c,
+			})
+		},
+		OnFound: func(path []*maze.CellIndex) {
+			r.recording.Path = path
+		},
+	}
+}
+
+// Recording returns what has been recorded so far.
+func (r *Recorder) Recording() maze.SearchRecording {
+	return r.recording
+}
+
+// MultiTracer returns a tracer that passes every event on to all the given
+// tracers, nil tracers are skipped.
+func MultiTracer(tracers ...*Tracer) *Tracer {
+	return &Tracer{
+		OnEnqueue: func(c maze.CellIndex) {
+			for _, t := range tracers {
+				t.enqueue(&c)
+			}
+		},
+		OnExpand: func(c maze.CellIndex) {
+			for _, t := range tracers {
+				t.expand(&c)
+			}
+		},
+		OnFound: func(path []*maze.CellIndex) {
+			for _, t := range tracers {
+				t.found(path)
+			}
+		},
+	}
+}
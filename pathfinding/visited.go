@@ -0,0 +1,33 @@
+package pathfinding
+
+import "github.com/cg14823/gomaze/maze"
+
+// visitedSet records which cells a search has seen without touching the maze,
+// so the same maze can be searched by many solvers at the same time.
+type visitedSet struct {
+	bits  []uint64
+	cols  int
+	count int
+}
+
+func newVisitedSet(m *maze.Maze) *visitedSet {
+	return &visitedSet{
+		bits: make([]uint64, (m.Rows*m.Cols+63)/64),
+		cols: m.Cols,
+	}
+}
+
+func (v *visitedSet) visit(c *maze.CellIndex) {
+	id := c.GetID(v.cols)
+	if v.bits[id/64]&(1<<uint(id%64)) != 0 {
+		return
+	}
+
+	v.bits[id/64] |= 1 << uint(id%64)
+	v.count++
+}
+
+func (v *visitedSet) has(c *maze.CellIndex) bool {
+	id := c.GetID(v.cols)
+	return v.bits[id/64]&(1<<uint(id%64)) != 0
+}
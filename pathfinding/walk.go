@@ -0,0 +1,359 @@
+package pathfinding
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cg14823/gomaze/maze"
+)
+
+// ErrWalkingInLoop is returned by the solvers that walk through the maze when they would walk in a loop forever
+// without reaching the end, like a wall follower starting next to a pillar.
+var ErrWalkingInLoop = errors.New("walking in a loop without reaching the end")
+
+func init() {
+	Register("wall-left", walkFunc(leftHandWalk))
+	Register("wall-right", walkFunc(rightHandWalk))
+	Register("pledge", walkFunc(pledgeWalk))
+	Register("tremaux", walkFunc(tremauxWalk))
+}
+
+// walkFunc is the shape of the solvers that walk through the maze a cell at a time like a person would. On
+// top of the path they report the route they walked, from the start, backtracking included.
+type walkFunc func(m *maze.Maze, t *Tracer) ([]*maze.CellIndex, error)
+
+func (f walkFunc) Solve(m *maze.Maze, t *Tracer) (*Result, error) {
+	start := time.Now()
+	route, err := f(m, t)
+	result := &Result{
+		Route:   route,
+		Steps:   uint64(len(route) - 1),
+		Visited: countCells(m, route),
+	}
+
+	if err == nil {
+		result.Path = pathFromRoute(route)
+		result.Cost = m.PathCost(result.Path)
+		t.found(result.Path)
+	}
+
+	result.Elapsed = time.Since(start)
+	return result, err
+}
+
+// walkPath runs f returning the path it found, from the end back to the start like BFS, and the route it
+// walked from the start, backtracking included.
+func walkPath(m *maze.Maze, f walkFunc) ([]*maze.CellIndex, []*maze.CellIndex, uint64, error) {
+	result, err := f.Solve(m, nil)
+	return result.Path, result.Route, result.Steps, err
+}
+
+// LeftHandWallFollower walks through the maze keeping its left hand on the wall and returns the path found
+// like BFS along with the route walked. It always reaches the end of a perfect maze but can walk in a loop
+// when the start or end are not next to a wall joined to the border.
+func LeftHandWallFollower(m *maze.Maze) ([]*maze.CellIndex, []*maze.CellIndex, uint64, error) {
+	return walkPath(m, leftHandWalk)
+}
+
+// RightHandWallFollower is LeftHandWallFollower with the right hand.
+func RightHandWallFollower(m *maze.Maze) ([]*maze.CellIndex, []*maze.CellIndex, uint64, error) {
+	return walkPath(m, rightHandWalk)
+}
+
+// Pledge walks straight towards the end until it hits a wall, then follows the wall with its left hand
+// counting the turns it makes until it faces its way again with as many turns to the left as to the right. It
+// gets around obstacles a wall follower would circle forever but it is made to escape mazes, so it can miss an
+// end inside the maze. The path and route are returned like LeftHandWallFollower does.
+func Pledge(m *maze.Maze) ([]*maze.CellIndex, []*maze.CellIndex, uint64, error) {
+	return walkPath(m, pledgeWalk)
+}
+
+// Tremaux walks through the maze marking every passage it takes. At a new junction it takes an unmarked
+// passage, when it comes back to a junction it has been to along a new passage it turns around, otherwise it
+// takes the passage with the fewest marks and never one marked twice. It always finds the end when there is
+// a way to it, walking every passage at most twice. The path and route are returned like
+// LeftHandWallFollower does.
+func Tremaux(m *maze.Maze) ([]*maze.CellIndex, []*maze.CellIndex, uint64, error) {
+	return walkPath(m, tremauxWalk)
+}
+
+// heading is the way a walker is facing, turning right adds one.
+type heading int
+
+const (
+	headingUp heading = iota
+	headingRight
+	headingDown
+	headingLeft
+)
+
+func (h heading) turn(quarters int) heading {
+	return heading((int(h) + quarters + 4) % 4)
+}
+
+// step returns the cell next to c in direction h.
+func (h heading) step(c maze.CellIndex) maze.CellIndex {
+	switch h {
+	case headingUp:
+		c.Row--
+	case headingRight:
+		c.Col++
+	case headingDown:
+		c.Row++
+	default:
+		c.Col--
+	}
+
+	return c
+}
+
+// open reports whether there is a passage out of c in direction h.
+func (h heading) open(m *maze.Maze, c maze.CellIndex) bool {
+	cell := &m.Cells[c.Row][c.Col]
+	switch h {
+	case headingUp:
+		return cell.Top
+	case headingRight:
+		return cell.Right
+	case headingDown:
+		return cell.Bottom
+	default:
+		return cell.Left
+	}
+}
+
+// startHeading faces into the maze from the border the start is on.
+func startHeading(m *maze.Maze) heading {
+	switch {
+	case m.Start.Row == m.Rows-1 && m.Rows > 1:
+		return headingUp
+	case m.Start.Col == 0 && m.Cols > 1:
+		return headingRight
+	case m.Start.Col == m.Cols-1 && m.Cols > 1:
+		return headingLeft
+	}
+
+	return headingDown
+}
+
+// handTurns are the turns a wall follower tries in order, keeping its hand on the wall.
+var handTurns = map[bool][]int{
+	true:  {-1, 0, 1, 2},
+	false: {1, 0, -1, -2},
+}
+
+// followWall takes one step keeping the left or right hand on the wall and returns the new cell and heading
+// and how many quarters it turned, negative to the left. It returns false when c is closed on every side.
+func followWall(m *maze.Maze, c maze.CellIndex, h heading, left bool) (maze.CellIndex, heading, int, bool) {
+	for _, turn := range handTurns[left] {
+		next := h.turn(turn)
+		if next.open(m, c) {
+			return next.step(c), next, turn, true
+		}
+	}
+
+	return c, h, 0, false
+}
+
+func leftHandWalk(m *maze.Maze, t *Tracer) ([]*maze.CellIndex, error) {
+	return wallFollowerWalk(m, t, true)
+}
+
+func rightHandWalk(m *maze.Maze, t *Tracer) ([]*maze.CellIndex, error) {
+	return wallFollowerWalk(m, t, false)
+}
+
+func wallFollowerWalk(m *maze.Maze, t *Tracer, left bool) ([]*maze.CellIndex, error) {
+	// a wall follower only depends on its cell and heading, being in the same one twice means it is in a loop
+	seen := make([]uint8, m.Rows*m.Cols)
+	c, h := m.Start, startHeading(m)
+	route := newRoute(c, t)
+	for c != m.End {
+		id := c.GetID(m.Cols)
+		if seen[id]&(1<<uint(h)) != 0 {
+			return route, ErrWalkingInLoop
+		}
+
+		seen[id] |= 1 << uint(h)
+		var ok bool
+		c, h, _, ok = followWall(m, c, h, left)
+		if !ok {
+			return route, ErrWalkingInLoop
+		}
+
+		route = walkTo(route, c, t)
+	}
+
+	return route, nil
+}
+
+func pledgeWalk(m *maze.Maze, t *Tracer) ([]*maze.CellIndex, error) {
+	way := headingDown
+	rows, cols := m.End.Row-m.Start.Row, m.End.Col-m.Start.Col
+	switch {
+	case abs(cols) >= abs(rows) && cols > 0:
+		way = headingRight
+	case abs(cols) >= abs(rows) && cols < 0:
+		way = headingLeft
+	case rows < 0:
+		way = headingUp
+	}
+
+	// the turns counted make the states endless so a limit on the steps is used instead, far more than
+	// following every wall of the maze takes
+	limit := 16*m.Rows*m.Cols + 16
+	c, h := m.Start, way
+	var turns int
+	route := newRoute(c, t)
+	for c != m.End {
+		if len(route) > limit {
+			return route, ErrWalkingInLoop
+		}
+
+		if turns == 0 && way.open(m, c) {
+			c, h = way.step(c), way
+			route = walkTo(route, c, t)
+			continue
+		}
+
+		var turn int
+		var ok bool
+		c, h, turn, ok = followWall(m, c, h, true)
+		if !ok {
+			return route, ErrWalkingInLoop
+		}
+
+		turns += turn
+		route = walkTo(route, c, t)
+	}
+
+	return route, nil
+}
+
+func tremauxWalk(m *maze.Maze, t *Tracer) ([]*maze.CellIndex, error) {
+	// marks holds how many times each passage was taken, the passage to the right and below of every cell
+	marks := [2][]uint8{
+		make([]uint8, m.Rows*m.Cols),
+		make([]uint8, m.Rows*m.Cols),
+	}
+
+	mark := func(c maze.CellIndex, h heading) *uint8 {
+		switch h {
+		case headingUp:
+			return &marks[1][c.GetID(m.Cols)-m.Cols]
+		case headingRight:
+			return &marks[0][c.GetID(m.Cols)]
+		case headingDown:
+			return &marks[1][c.GetID(m.Cols)]
+		default:
+			return &marks[0][c.GetID(m.Cols)-1]
+		}
+	}
+
+	c := m.Start
+	route := newRoute(c, t)
+	arrived := false
+	var back heading
+	for c != m.End {
+		var others []heading
+		newJunction := true
+		for h := headingUp; h <= headingLeft; h++ {
+			if !h.open(m, c) || (arrived && h == back) {
+				continue
+			}
+
+			others = append(others, h)
+			if *mark(c, h) > 0 {
+				newJunction = false
+			}
+		}
+
+		next := back
+		switch {
+		case len(others) == 0 && !arrived:
+			return route, errors.New("could not find path")
+		case len(others) == 0:
+			// a dead end, go back
+		case arrived && !newJunction && *mark(c, back) == 1:
+			// an old junction reached along a new passage, go back
+		default:
+			next = others[0]
+			for _, h := range others[1:] {
+				if *mark(c, h) < *mark(c, next) {
+					next = h
+				}
+			}
+		}
+
+		if *mark(c, next) >= 2 {
+			// every passage has been walked twice, back at the start with no way to the end
+			return route, errors.New("could not find path")
+		}
+
+		*mark(c, next)++
+		c = next.step(c)
+		back = next.turn(2)
+		arrived = true
+		route = walkTo(route, c, t)
+	}
+
+	return route, nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+func newRoute(start maze.CellIndex, t *Tracer) []*maze.CellIndex {
+	t.enqueue(&start)
+	t.expand(&start)
+	return []*maze.CellIndex{&start}
+}
+
+// walkTo adds c to the route.
+func walkTo(route []*maze.CellIndex, c maze.CellIndex, t *Tracer) []*maze.CellIndex {
+	t.enqueue(&c)
+	t.expand(&c)
+	return append(route, &c)
+}
+
+// pathFromRoute removes the loops and dead ends walked into from the route, leaving a path from the end of the
+// route back to its start.
+func pathFromRoute(route []*maze.CellIndex) []*maze.CellIndex {
+	onPath := make(map[maze.CellIndex]int)
+	path := make([]*maze.CellIndex, 0)
+	for _, c := range route {
+		if i, ok := onPath[*c]; ok {
+			// coming back to a cell drops everything walked since
+			for _, dropped := range path[i+1:] {
+				delete(onPath, *dropped)
+			}
+
+			path = path[:i+1]
+			continue
+		}
+
+		onPath[*c] = len(path)
+		path = append(path, c)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
+
+// countCells returns the number of different cells on route.
+func countCells(m *maze.Maze, route []*maze.CellIndex) int {
+	visited := newVisitedSet(m)
+	for _, c := range route {
+		visited.visit(c)
+	}
+
+	return visited.count
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/cg14823/gomaze/game"
+	"github.com/cg14823/gomaze/maze"
+)
+
+// runPlay lets the player walk through a new maze, or a loaded one when -in is given, in the terminal.
+func runPlay(fs *flag.FlagSet, args []string) error {
+	var in string
+	var termWidth, pitch int
+	generator := addGeneratorFlags(fs, 15)
+	fs.StringVar(&in, "in", "", "Maze to play instead of generating one, see gomaze render -h")
+	fs.IntVar(&pitch, "pitch", 0, "Pixels from one cell to the next when -in is an image")
+	fs.IntVar(&termWidth, "term-width", 0, "Columns the maze is fitted in, by default $COLUMNS or 80")
+	err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if termWidth == 0 {
+		termWidth = terminalWidth()
+	}
+
+	var m *maze.Maze
+	if in != "" {
+		m, err = loadMaze(in, pitch)
+	} else {
+		m, err = generator.generate("", 0)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	restore, err := game.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return fmt.Errorf("cannot play: stdin is %w", err)
+	}
+	defer restore()
+
+	// the keys are read from a file of their own on the terminal, unlike stdin it can be given a deadline so
+	// Play stops reading them once the game is over
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return fmt.Errorf("cannot play: %w", err)
+	}
+	defer tty.Close()
+
+	return game.Play(game.New(m), tty, os.Stdout, termWidth)
+}
@@ -0,0 +1,24 @@
+package main
+
+import "flag"
+
+func runRender(fs *flag.FlagSet, args []string) error {
+	input := addInputFlags(fs)
+	output := addOutputFlags(fs)
+	err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	m, err := input.load()
+	if err != nil {
+		return err
+	}
+
+	format, fileOut, err := output.resolve(m)
+	if err != nil {
+		return err
+	}
+
+	return writeImage(m, fileOut, format, nil, nil)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"time"
+
+	"github.com/cg14823/gomaze/server"
+)
+
+func runServe(fs *flag.FlagSet, args []string) error {
+	var addr string
+	var config server.Config
+	fs.StringVar(&addr, "addr", ":8080", "Address to listen on")
+	fs.IntVar(&config.MaxCells, "max-cells", 250000, "The most cells a maze can be generated with")
+	fs.IntVar(&config.StoreSize, "store-size", 1000,
+		"Number of mazes kept in memory, the least recently used are forgotten first")
+	fs.DurationVar(&config.Timeout, "timeout", 10*time.Second, "How long a request can take")
+	fs.IntVar(&config.MaxWorkers, "max-workers", runtime.NumCPU(),
+		"The most mazes generated, drawn or solved at once, other requests wait for their turn")
+	err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if config.MaxCells < 1 || config.StoreSize < 1 || config.Timeout <= 0 || config.MaxWorkers < 1 {
+		return usageErrorf("-max-cells, -store-size, -timeout and -max-workers must be positive")
+	}
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           server.New(config),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		// the handler gives up after config.Timeout so there is time left to write the response
+		WriteTimeout:   config.Timeout + 10*time.Second,
+		IdleTimeout:    time.Minute,
+		MaxHeaderBytes: 1 << 16,
+	}
+
+	shutdown := make(chan error, 1)
+	go func() {
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		<-interrupt
+
+		ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+		defer cancel()
+		shutdown <- srv.Shutdown(ctx)
+	}()
+
+	fmt.Fprintln(os.Stderr, "Listening on", addr)
+	err = srv.ListenAndServe()
+	if err != http.ErrServerClosed {
+		return err
+	}
+
+	return <-shutdown
+}
@@ -0,0 +1,450 @@
+// Package server serves mazes over HTTP so they can be generated, drawn and solved without running gomaze.
+//
+// The endpoints are:
+//
+//	POST /mazes                      generate a maze from a JSON body with rows, cols, algorithm, placement, maxCost and seed
+//	GET  /mazes/{id}.png             draw a maze as a PNG image
+//	GET  /mazes/{id}.svg             draw a maze as an SVG image
+//	GET  /mazes/{id}.json            get a maze in the format written by maze.Save
+//	POST /mazes/{id}/solve?algo=     solve a maze, by default with astar
+//
+// Errors are returned as JSON with an error field, a solver that cannot solve the maze, like a wall follower
+// walking in a loop, gets a 422. Mazes are only kept in memory, once the store is full the
+// least recently used ones are forgotten. Only a few mazes are generated, drawn or solved at once, requests
+// wait for their turn until they time out.
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/cg14823/gomaze/maze"
+	"github.com/cg14823/gomaze/pathfinding"
+)
+
+// Config limits what a Server does, zero values are replaced by the defaults.
+type Config struct {
+	// MaxCells is the most cells a maze can have, 250000 by default.
+	MaxCells int
+	// MaxBodyBytes is the biggest request body accepted, 1KiB by default.
+	MaxBodyBytes int64
+	// StoreSize is the number of mazes kept in memory, 1000 by default.
+	StoreSize int
+	// Timeout is how long a request can take before a 503 is returned instead, 10 seconds by default.
+	Timeout time.Duration
+	// MaxWorkers is the most mazes generated, drawn or solved at once, the number of CPUs by default.
+	MaxWorkers int
+}
+
+const (
+	defaultMaxCells     = 250000
+	defaultMaxBodyBytes = 1 << 10
+	defaultStoreSize    = 1000
+	defaultTimeout      = 10 * time.Second
+	defaultGenerator    = "prim"
+	defaultPlacement    = "border"
+	defaultSolver       = "astar"
+)
+
+// Server is an http.Handler serving the maze API.
+type Server struct {
+	config Config
+	mazes  *store
+	// workers holds a value for every maze being generated, drawn or solved
+	workers chan struct{}
+	handler http.Handler
+}
+
+// New returns a Server limited by config.
+func New(config Config) *Server {
+	if config.MaxCells <= 0 {
+		config.MaxCells = defaultMaxCells
+	}
+
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = defaultMaxBodyBytes
+	}
+
+	if config.StoreSize <= 0 {
+		config.StoreSize = defaultStoreSize
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	if config.MaxWorkers <= 0 {
+		config.MaxWorkers = runtime.NumCPU()
+	}
+
+	s := &Server{
+		config:  config,
+		mazes:   newStore(config.StoreSize),
+		workers: make(chan struct{}, config.MaxWorkers),
+	}
+
+	s.handler = http.TimeoutHandler(http.HandlerFunc(s.route), config.Timeout, `{"error":"request timed out"}`)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// route picks the handler from the path and method of r, the routes are few enough to match by hand.
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/mazes" {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		s.createMaze(w, r)
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, "/mazes/")
+	if rest == r.URL.Path || rest == "" {
+		writeError(w, http.StatusNotFound, "no such endpoint %s", r.URL.Path)
+		return
+	}
+
+	if id := strings.TrimSuffix(rest, "/solve"); id != rest {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		s.solveMaze(w, r, id)
+		return
+	}
+
+	ext := path.Ext(rest)
+	if strings.Contains(rest, "/") || ext == "" {
+		writeError(w, http.StatusNotFound, "no such endpoint %s", r.URL.Path)
+		return
+	}
+
+	if !allowMethod(w, r, http.MethodGet, http.MethodHead) {
+		return
+	}
+
+	s.getMaze(w, r, strings.TrimSuffix(rest, ext), ext)
+}
+
+// allowMethod reports whether r uses one of methods, writing a 405 when it does not.
+func allowMethod(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
+		}
+	}
+
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	return false
+}
+
+type cellJSON struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+// mazeInfo describes a stored maze, it is what POST /mazes returns.
+type mazeInfo struct {
+	ID        string   `json:"id"`
+	Rows      int      `json:"rows"`
+	Cols      int      `json:"cols"`
+	Algorithm string   `json:"algorithm"`
+	Placement string   `json:"placement"`
+	Seed      int64    `json:"seed"`
+	Start     cellJSON `json:"start"`
+	End       cellJSON `json:"end"`
+}
+
+type createRequest struct {
+	Rows      int    `json:"rows"`
+	Cols      int    `json:"cols"`
+	Algorithm string `json:"algorithm"`
+	Placement string `json:"placement"`
+	// MaxCost covers the maze in patches costing up to it to move through when it is more than 1.
+	MaxCost int `json:"maxCost"`
+	// Seed is a pointer so 0 can be told apart from no seed, which picks a random one.
+	Seed *int64 `json:"seed"`
+}
+
+func (s *Server) createMaze(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if !s.decodeBody(w, r, &req) {
+		return
+	}
+
+	if req.Rows < 1 || req.Cols < 1 {
+		writeError(w, http.StatusBadRequest, "the maze needs at least one row and one column")
+		return
+	}
+
+	// compared by dividing so huge sizes cannot overflow
+	if req.Rows > s.config.MaxCells/req.Cols {
+		writeError(w, http.StatusBadRequest, "the maze can have at most %d cells", s.config.MaxCells)
+		return
+	}
+
+	if req.MaxCost < 0 || req.MaxCost > maze.MaxCost {
+		writeError(w, http.StatusBadRequest, "maxCost must be between 0 and %d", maze.MaxCost)
+		return
+	}
+
+	if req.Algorithm == "" {
+		req.Algorithm = defaultGenerator
+	}
+
+	generator, err := maze.GetGenerator(req.Algorithm)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s, available are %v", err.Error(), maze.GeneratorNames())
+		return
+	}
+
+	if req.Placement == "" {
+		req.Placement = defaultPlacement
+	}
+
+	placement, err := maze.GetPlacement(req.Placement)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s, available are %v", err.Error(), maze.PlacementNames())
+		return
+	}
+
+	id, err := newID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not pick an id: %s", err.Error())
+		return
+	}
+
+	opts := []maze.Option{
+		maze.WithGenerator(generator),
+		maze.WithPlacement(placement),
+		maze.WithRandomCosts(uint8(req.MaxCost)),
+	}
+	if req.Seed != nil {
+		opts = append(opts, maze.WithSeed(*req.Seed))
+	}
+
+	var m *maze.Maze
+	if !s.run(r, func() { m = maze.NewMaze(req.Rows, req.Cols, opts...) }) {
+		// the client was told the request timed out so the maze is never stored, its id would be unknown
+		return
+	}
+
+	info := mazeInfo{
+		ID:        id,
+		Rows:      m.Rows,
+		Cols:      m.Cols,
+		Algorithm: req.Algorithm,
+		Placement: req.Placement,
+		Seed:      m.Seed,
+		Start:     toCellJSON(m.Start),
+		End:       toCellJSON(m.End),
+	}
+
+	s.mazes.add(id, m)
+	w.Header().Set("Location", "/mazes/"+id+".json")
+	writeJSON(w, http.StatusCreated, info)
+}
+
+// decodeBody reads the JSON body of r into v, writing an error when it is too big or not valid.
+func (s *Server) decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	// one more byte than allowed is read to tell a body of exactly the limit from a longer one
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, s.config.MaxBodyBytes+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "could not read body: %s", err.Error())
+		return false
+	}
+
+	if int64(len(body)) > s.config.MaxBodyBytes {
+		writeError(w, http.StatusRequestEntityTooLarge, "body is larger than %d bytes", s.config.MaxBodyBytes)
+		return false
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(v)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid body: %s", err.Error())
+		return false
+	}
+
+	return true
+}
+
+func (s *Server) getMaze(w http.ResponseWriter, r *http.Request, id, ext string) {
+	m, ok := s.mazes.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "no maze with id %q", id)
+		return
+	}
+
+	var contentType string
+	var write func(w io.Writer) error
+	switch ext {
+	case ".png":
+		contentType = "image/png"
+		write = func(w io.Writer) error {
+			return m.WritePNG(w, nil, nil)
+		}
+	case ".svg":
+		contentType = "image/svg+xml"
+		write = func(w io.Writer) error {
+			return m.WriteSVG(w, nil, nil)
+		}
+	case ".json":
+		contentType = "application/json"
+		write = m.Save
+	default:
+		writeError(w, http.StatusNotFound, "unknown format %q, available are .png, .svg and .json", ext)
+		return
+	}
+
+	// drawn to a buffer first so a failure can still be reported with the right status
+	var buf bytes.Buffer
+	var err error
+	if !s.run(r, func() { err = write(&buf) }) {
+		return
+	}
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "could not draw maze: %s", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", fmt.Sprint(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+type solveResponse struct {
+	Algorithm string `json:"algorithm"`
+	// Path goes from the start of the maze to the end.
+	Path    []cellJSON `json:"path"`
+	Length  int        `json:"length"`
+	Steps   uint64     `json:"steps"`
+	Visited int        `json:"visited"`
+	Cost    int        `json:"cost"`
+	// ElapsedMicroseconds is how long the solver took, without the time spent on the request.
+	ElapsedMicroseconds int64 `json:"elapsedMicroseconds"`
+}
+
+func (s *Server) solveMaze(w http.ResponseWriter, r *http.Request, id string) {
+	algo := r.URL.Query().Get("algo")
+	if algo == "" {
+		algo = defaultSolver
+	}
+
+	solver, err := pathfinding.Get(algo)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s, available are %v", err.Error(), pathfinding.List())
+		return
+	}
+
+	m, ok := s.mazes.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "no maze with id %q", id)
+		return
+	}
+
+	// the built in solvers keep their own state so mazes can be solved by many requests at once
+	var result *pathfinding.Result
+	if !s.run(r, func() { result, err = solver.Solve(m, nil) }) {
+		return
+	}
+
+	// solvers like the wall followers cannot solve every maze, that is down to the maze and the algorithm
+	// asked for rather than the server
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "%s failed to find a path after %d steps: %s", algo,
+			result.Steps, err.Error())
+		return
+	}
+
+	path := make([]cellJSON, len(result.Path))
+	for i, c := range result.Path {
+		path[len(path)-1-i] = toCellJSON(*c)
+	}
+
+	writeJSON(w, http.StatusOK, solveResponse{
+		Algorithm:           algo,
+		Path:                path,
+		Length:              len(path),
+		Steps:               result.Steps,
+		Visited:             result.Visited,
+		Cost:                result.Cost,
+		ElapsedMicroseconds: result.Elapsed.Microseconds(),
+	})
+}
+
+// run calls work in its own goroutine once fewer than MaxWorkers are running and waits for it to finish. It
+// returns false when http.TimeoutHandler gives up on r first, the client has been answered by then so the work
+// done for it must be thrown away rather than stored or sent. Work cannot be stopped half way so it runs to
+// the end holding its place, that way slow requests cannot pile up more than MaxWorkers of it.
+func (s *Server) run(r *http.Request, work func()) bool {
+	select {
+	case s.workers <- struct{}{}:
+	case <-r.Context().Done():
+		return false
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer func() { <-s.workers }()
+		work()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return r.Context().Err() == nil
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+func toCellJSON(c maze.CellIndex) cellJSON {
+	return cellJSON{
+		Row: c.Row,
+		Col: c.Col,
+	}
+}
+
+// newID returns a random id that is hard to guess, so mazes of other users cannot be found by counting.
+func newID() (string, error) {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{
+		Error: fmt.Sprintf(format, args...),
+	})
+}
@@ -0,0 +1,214 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cg14823/gomaze/maze"
+	"github.com/cg14823/gomaze/pathfinding"
+)
+
+func init() {
+	pathfinding.Register("test-lost", pathfinding.SolverFunc(func(m *maze.Maze) ([]*maze.CellIndex, uint64, error) {
+		return nil, 12, errors.New("lost")
+	}))
+}
+
+// do sends a request to s and returns the response, decoding a JSON body into v when it is not nil.
+func do(t *testing.T, s *Server, method, target, body string, v interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	if v != nil {
+		err := json.NewDecoder(w.Body).Decode(v)
+		if err != nil {
+			t.Fatalf("could not decode response %d: %v", w.Code, err)
+		}
+	}
+
+	return w
+}
+
+// create generates a maze on s from body and returns what it says about it.
+func create(t *testing.T, s *Server, body string) mazeInfo {
+	t.Helper()
+	var info mazeInfo
+	w := do(t, s, http.MethodPost, "/mazes", body, &info)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got status %d creating %s, want %d", w.Code, body, http.StatusCreated)
+	}
+
+	if w.Header().Get("Location") != "/mazes/"+info.ID+".json" {
+		t.Fatalf("got location %q for maze %q", w.Header().Get("Location"), info.ID)
+	}
+
+	return info
+}
+
+func TestCreateAndGet(t *testing.T) {
+	s := New(Config{})
+	var info mazeInfo
+	w := do(t, s, http.MethodPost, "/mazes", `{"rows": 4, "cols": 6, "algorithm": "wilson", "seed": 4}`, &info)
+	if w.Code != http.StatusCreated || info.Rows != 4 || info.Cols != 6 || info.Algorithm != "wilson" ||
+		info.Placement != defaultPlacement || info.Seed != 4 {
+		t.Fatalf("got status %d and %+v", w.Code, info)
+	}
+
+	want := maze.NewMaze(4, 6, maze.WithGenerator(maze.Wilson{}), maze.WithPlacement(maze.RandomBorder{}),
+		maze.WithSeed(4))
+	for ext, contentType := range map[string]string{
+		".png":  "image/png",
+		".svg":  "image/svg+xml",
+		".json": "application/json",
+	} {
+		w := do(t, s, http.MethodGet, "/mazes/"+info.ID+ext, "", nil)
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != contentType || w.Body.Len() == 0 {
+			t.Fatalf("got status %d, %q and %d bytes for %s", w.Code, w.Header().Get("Content-Type"),
+				w.Body.Len(), ext)
+		}
+
+		if ext == ".json" {
+			got, err := maze.Load(w.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Start != want.Start || got.End != want.End || fmt.Sprint(got.Cells) != fmt.Sprint(want.Cells) {
+				t.Fatalf("got a different maze than the one generated with the same seed")
+			}
+		}
+	}
+}
+
+func TestSolve(t *testing.T) {
+	s := New(Config{})
+	info := create(t, s, `{"rows": 10, "cols": 12, "placement": "corners", "seed": 2}`)
+	var result solveResponse
+	w := do(t, s, http.MethodPost, "/mazes/"+info.ID+"/solve?algo=bfs", "", &result)
+	if w.Code != http.StatusOK || result.Algorithm != "bfs" || result.Length != len(result.Path) {
+		t.Fatalf("got status %d and %+v", w.Code, result)
+	}
+
+	first, last := result.Path[0], result.Path[len(result.Path)-1]
+	if first != info.Start || last != info.End {
+		t.Fatalf("path goes from %+v to %+v, want from the start %+v to the end %+v", first, last, info.Start,
+			info.End)
+	}
+
+	var failed struct {
+		Error string `json:"error"`
+	}
+	w = do(t, s, http.MethodPost, "/mazes/"+info.ID+"/solve?algo=test-lost", "", &failed)
+	want := "test-lost failed to find a path after 12 steps: lost"
+	if w.Code != http.StatusUnprocessableEntity || failed.Error != want {
+		t.Fatalf("got status %d and %q, want %d and %q", w.Code, failed.Error, http.StatusUnprocessableEntity, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s := New(Config{MaxCells: 400, MaxBodyBytes: 64})
+	id := create(t, s, `{"rows": 20, "cols": 20}`).ID
+	tests := []struct {
+		name, method, target, body string
+		want                       int
+	}{
+		{"too many cells", http.MethodPost, "/mazes", `{"rows": 20, "cols": 21}`, http.StatusBadRequest},
+		{"huge size", http.MethodPost, "/mazes", `{"rows": 9223372036854775807, "cols": 2}`, http.StatusBadRequest},
+		{"no rows", http.MethodPost, "/mazes", `{"rows": 0, "cols": 2}`, http.StatusBadRequest},
+		{"body too big", http.MethodPost, "/mazes", `{"rows": 2, "cols": 2, "algorithm": "` +
+			strings.Repeat("a", 64) + `"}`, http.StatusRequestEntityTooLarge},
+		{"bad json", http.MethodPost, "/mazes", `{"rows": 2,`, http.StatusBadRequest},
+		{"unknown field", http.MethodPost, "/mazes", `{"rows": 2, "cols": 2, "size": 2}`, http.StatusBadRequest},
+		{"unknown generator", http.MethodPost, "/mazes", `{"rows": 2, "cols": 2, "algorithm": "x"}`,
+			http.StatusBadRequest},
+		{"unknown placement", http.MethodPost, "/mazes", `{"rows": 2, "cols": 2, "placement": "x"}`,
+			http.StatusBadRequest},
+		{"bad cost", http.MethodPost, "/mazes", `{"rows": 2, "cols": 2, "maxCost": 256}`, http.StatusBadRequest},
+		{"get mazes", http.MethodGet, "/mazes", "", http.StatusMethodNotAllowed},
+		{"post image", http.MethodPost, "/mazes/" + id + ".png", "", http.StatusMethodNotAllowed},
+		{"get solve", http.MethodGet, "/mazes/" + id + "/solve", "", http.StatusMethodNotAllowed},
+		{"unknown maze", http.MethodGet, "/mazes/nope.png", "", http.StatusNotFound},
+		{"unknown format", http.MethodGet, "/mazes/" + id + ".gif", "", http.StatusNotFound},
+		{"no format", http.MethodGet, "/mazes/" + id, "", http.StatusNotFound},
+		{"unknown endpoint", http.MethodGet, "/other", "", http.StatusNotFound},
+		{"solve unknown maze", http.MethodPost, "/mazes/nope/solve", "", http.StatusNotFound},
+		{"unknown solver", http.MethodPost, "/mazes/" + id + "/solve?algo=x", "", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body struct {
+				Error string `json:"error"`
+			}
+			w := do(t, s, tt.method, tt.target, tt.body, &body)
+			if w.Code != tt.want || body.Error == "" {
+				t.Fatalf("got status %d and error %q, want %d", w.Code, body.Error, tt.want)
+			}
+
+			if tt.want == http.StatusMethodNotAllowed && w.Header().Get("Allow") == "" {
+				t.Fatal("405 without an Allow header")
+			}
+		})
+	}
+}
+
+func TestStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	s := New(Config{StoreSize: 2})
+	a := create(t, s, `{"rows": 2, "cols": 2}`).ID
+	b := create(t, s, `{"rows": 2, "cols": 2}`).ID
+	do(t, s, http.MethodGet, "/mazes/"+a+".json", "", nil)
+	c := create(t, s, `{"rows": 2, "cols": 2}`).ID
+	for id, want := range map[string]int{a: http.StatusOK, b: http.StatusNotFound, c: http.StatusOK} {
+		if w := do(t, s, http.MethodGet, "/mazes/"+id+".json", "", nil); w.Code != want {
+			t.Fatalf("got status %d for maze %s, want %d", w.Code, id, want)
+		}
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	s := New(Config{Timeout: 50 * time.Millisecond, MaxWorkers: 1})
+	id := create(t, s, `{"rows": 2, "cols": 2}`).ID
+
+	// every worker is busy so the requests wait for their turn until they time out
+	s.workers <- struct{}{}
+	requests := []struct{ method, target string }{
+		{http.MethodPost, "/mazes"},
+		{http.MethodGet, "/mazes/" + id + ".png"},
+		{http.MethodPost, "/mazes/" + id + "/solve"},
+	}
+
+	for _, r := range requests {
+		w := do(t, s, r.method, r.target, `{"rows": 2, "cols": 2}`, nil)
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("got status %d for %s %s, want %d", w.Code, r.method, r.target, http.StatusServiceUnavailable)
+		}
+	}
+
+	<-s.workers
+	if n := s.mazes.order.Len(); n != 1 {
+		t.Fatalf("%d mazes stored, the maze of the request that timed out must not be kept", n)
+	}
+
+	// work that outlives its request holds its place until it finishes
+	started, block := make(chan struct{}), make(chan struct{})
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	go s.run(r, func() {
+		close(started)
+		<-block
+	})
+
+	<-started
+	w := do(t, s, http.MethodPost, "/mazes", `{"rows": 2, "cols": 2}`, nil)
+	close(block)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("got status %d while the only worker is busy, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	create(t, s, `{"rows": 2, "cols": 2}`)
+}
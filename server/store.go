@@ -0,0 +1,67 @@
+package server
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/cg14823/gomaze/maze"
+)
+
+// store keeps the most recently used mazes in memory, once it holds size mazes adding another evicts the one
+// used least recently. It is safe to use from many goroutines.
+type store struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	// mazes holds the elements of order, whose values are entries, by id
+	mazes map[string]*list.Element
+}
+
+type entry struct {
+	id   string
+	maze *maze.Maze
+}
+
+func newStore(size int) *store {
+	return &store{
+		size:  size,
+		order: list.New(),
+		mazes: make(map[string]*list.Element),
+	}
+}
+
+// add stores m under id, evicting the least recently used mazes to make room.
+func (s *store) add(id string, m *maze.Maze) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.mazes[id]; ok {
+		s.order.Remove(e)
+	}
+
+	s.mazes[id] = s.order.PushFront(&entry{
+		id:   id,
+		maze: m,
+	})
+
+	for s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.mazes, oldest.Value.(*entry).id)
+	}
+}
+
+// get returns the maze stored under id and marks it as the most recently used. Mazes are shared between
+// requests so they must not be changed.
+func (s *store) get(id string) (*maze.Maze, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.mazes[id]
+	if !ok {
+		return nil, false
+	}
+
+	s.order.MoveToFront(e)
+	return e.Value.(*entry).maze, true
+}
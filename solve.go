@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/color"
+
+	"github.com/cg14823/gomaze/maze"
+	"github.com/cg14823/gomaze/pathfinding"
+)
+
+func runSolve(fs *flag.FlagSet, args []string) error {
+	var algo string
+	input := addInputFlags(fs)
+	output := addOutputFlags(fs)
+	animation := addAnimationFlags(fs)
+	fs.StringVar(&algo, "algo", "astar", fmt.Sprintf("The path finding algorithm to use available are %v",
+		pathfinding.List()))
+	err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	solver, err := pathfinding.Get(algo)
+	if err != nil {
+		return &usageError{msg: err.Error()}
+	}
+
+	m, err := input.load()
+	if err != nil {
+		return err
+	}
+
+	format, fileOut, err := output.resolve(m)
+	if err != nil {
+		return err
+	}
+
+	tracer, recorder := animation.searchTracer(algo)
+	result, err := solver.Solve(m, tracer)
+	if err != nil {
+		return fmt.Errorf("%s failed to find path after %d steps: %w", algo, result.Steps, err)
+	}
+
+	printResult(m, algo, result)
+	if animation.fileOut != "" {
+		err = saveSearchAnimation(m, animation.fileOut, animation.every, []*pathfinding.Recorder{recorder})
+		if err != nil {
+			return err
+		}
+	}
+
+	paths := [][]*maze.CellIndex{result.Path}
+	colours := []color.Color{color.RGBA{
+		R: 100,
+		G: 0,
+		B: 100,
+		A: 255,
+	}}
+
+	// the solvers that walk the maze also show the way they went, under the path
+	if result.Route != nil {
+		paths = append([][]*maze.CellIndex{result.Route}, paths...)
+		colours = append([]color.Color{color.RGBA{
+			R: 230,
+			G: 190,
+			B: 230,
+			A: 255,
+		}}, colours...)
+	}
+
+	return writeImage(m, fileOut, format, paths, colours)
+}
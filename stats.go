@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+func runStats(fs *flag.FlagSet, args []string) error {
+	var asJSON bool
+	input := addInputFlags(fs)
+	fs.BoolVar(&asJSON, "json", false, "Print the statistics as JSON")
+	err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	m, err := input.load()
+	if err != nil {
+		return err
+	}
+
+	stats, err := m.Stats()
+	if err != nil {
+		return fmt.Errorf("maze has no solution: %w", err)
+	}
+
+	if asJSON {
+		return json.NewEncoder(os.Stdout).Encode(stats)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "size\t%dx%d\n", m.Rows, m.Cols)
+	fmt.Fprintf(w, "cells\t%d\n", stats.Cells)
+	fmt.Fprintf(w, "seed\t%d\n", m.Seed)
+	fmt.Fprintf(w, "start\trow %d col %d\n", m.Start.Row, m.Start.Col)
+	fmt.Fprintf(w, "end\trow %d col %d\n", m.End.Row, m.End.Col)
+	fmt.Fprintf(w, "dead ends\t%d (%.1f%%)\n", stats.DeadEnds, stats.DeadEndPercent)
+	fmt.Fprintf(w, "junctions\t%d\n", stats.Junctions)
+	fmt.Fprintf(w, "corridors\t%d\n", stats.Corridors)
+	fmt.Fprintf(w, "turns\t%d\n", stats.Turns)
+	fmt.Fprintf(w, "longest corridor\t%d\n", stats.LongestCorridor)
+	fmt.Fprintf(w, "solution length\t%d\n", stats.SolutionLength)
+	fmt.Fprintf(w, "decision points\t%d\n", stats.DecisionPoints)
+	fmt.Fprintf(w, "mean dead end depth\t%.2f\n", stats.MeanDeadEndDepth)
+	fmt.Fprintf(w, "difficulty\t%.2f\n", stats.Difficulty)
+	return w.Flush()
+}